@@ -0,0 +1,108 @@
+package discordgo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+const dcaMagic = "DCA1"
+
+//DCAMetadata is the json header of a DCA1 file, DCA0 files have no metadata
+type DCAMetadata struct {
+	Dca struct {
+		Version int `json:"version"`
+		Tool    struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+			URL     string `json:"url"`
+			Author  string `json:"author"`
+		} `json:"tool"`
+	} `json:"dca"`
+	Opus struct {
+		Mode       string `json:"mode"`
+		SampleRate int    `json:"sample_rate"`
+		FrameSize  int    `json:"frame_size"`
+		Abr        int    `json:"abr"`
+		Vbr        bool   `json:"vbr"`
+		Channels   int    `json:"channels"`
+	} `json:"opus"`
+	Info struct {
+		Title    string `json:"title"`
+		Artist   string `json:"artist"`
+		Album    string `json:"album"`
+		Genre    string `json:"genre"`
+		Comments string `json:"comments"`
+		Cover    string `json:"cover"`
+	} `json:"info"`
+	Origin struct {
+		Source   string `json:"source"`
+		Abr      int    `json:"abr"`
+		Channels int    `json:"channels"`
+		Encoding string `json:"encoding"`
+		URL      string `json:"url"`
+	} `json:"origin"`
+	Extra json.RawMessage `json:"extra"`
+}
+
+//DCAReader reads the opus frames of a DCA file, Metadata is nil for DCA0 files
+type DCAReader struct {
+	Metadata *DCAMetadata
+	r        *bufio.Reader
+}
+
+//NewDCAReader reads the DCA1 header if there is one, files without the DCA1 magic are read as DCA0
+func NewDCAReader(r io.Reader) (*DCAReader, error) {
+	d := &DCAReader{r: bufio.NewReader(r)}
+	magic, err := d.r.Peek(len(dcaMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if string(magic) != dcaMagic {
+		return d, nil
+	}
+
+	d.r.Discard(len(dcaMagic))
+	var size int32
+	err = binary.Read(d.r, binary.LittleEndian, &size)
+	if err != nil {
+		return nil, err
+	}
+	if size < 0 {
+		return nil, errors.New("dca: negative metadata size")
+	}
+	header := make([]byte, size)
+	_, err = io.ReadFull(d.r, header)
+	if err != nil {
+		return nil, err
+	}
+	d.Metadata = &DCAMetadata{}
+	err = json.Unmarshal(header, d.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+//OpusFrame returns the next frame, io.EOF after the last one
+func (d *DCAReader) OpusFrame() ([]byte, error) {
+	var size int16
+	err := binary.Read(d.r, binary.LittleEndian, &size)
+	if err != nil {
+		return nil, err
+	}
+	if size < 0 {
+		return nil, errors.New("dca: negative frame size")
+	}
+	frame := make([]byte, size)
+	_, err = io.ReadFull(d.r, frame)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
@@ -0,0 +1,78 @@
+package discordgo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+func dcaFrames(frames ...[]byte) []byte {
+	var buf bytes.Buffer
+	for _, frame := range frames {
+		binary.Write(&buf, binary.LittleEndian, int16(len(frame)))
+		buf.Write(frame)
+	}
+	return buf.Bytes()
+}
+
+func TestDCAReader(t *testing.T) {
+	meta := []byte(`{"dca":{"version":1},"opus":{"sample_rate":48000,"frame_size":960,"channels":2},"info":{"title":"song"}}`)
+	var dca1 bytes.Buffer
+	dca1.WriteString("DCA1")
+	binary.Write(&dca1, binary.LittleEndian, int32(len(meta)))
+	dca1.Write(meta)
+	dca1.Write(dcaFrames([]byte{1, 2, 3}, []byte{4}))
+
+	tests := []struct {
+		name   string
+		input  []byte
+		title  string
+		frames [][]byte
+	}{
+		{"dca0", dcaFrames([]byte{1, 2, 3}, []byte{4}), "", [][]byte{{1, 2, 3}, {4}}},
+		{"dca1", dca1.Bytes(), "song", [][]byte{{1, 2, 3}, {4}}},
+		{"empty", nil, "", nil},
+	}
+	for _, test := range tests {
+		r, err := NewDCAReader(bytes.NewReader(test.input))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if (r.Metadata != nil) != (test.title != "") || (r.Metadata != nil && r.Metadata.Info.Title != test.title) {
+			t.Errorf("%s: metadata %+v", test.name, r.Metadata)
+		}
+		for i, want := range test.frames {
+			frame, err := r.OpusFrame()
+			if err != nil || !bytes.Equal(frame, want) {
+				t.Errorf("%s: frame %d is %v (%v), want %v", test.name, i, frame, err, want)
+			}
+		}
+		if _, err := r.OpusFrame(); err != io.EOF {
+			t.Errorf("%s: expected io.EOF after the last frame, got %v", test.name, err)
+		}
+	}
+}
+
+func TestDCAReaderTruncatedFrame(t *testing.T) {
+	input := dcaFrames([]byte{1, 2, 3})
+	r, _ := NewDCAReader(bytes.NewReader(input[:len(input)-1]))
+	if _, err := r.OpusFrame(); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestRTPSequencer(t *testing.T) {
+	s := NewRTPSequencer(0x01020304)
+	s.header.Sequence = 0xFFFF
+	s.header.Timestamp = 0
+	first := s.Next()
+	second := s.Next()
+	if second.Sequence != 0 || second.Timestamp != first.Timestamp+OPUS_FRAME_SIZE {
+		t.Errorf("unexpected second header %+v after %+v", second, first)
+	}
+	want := []byte{0x80, 0x78, 0, 0, 0, 0, 0x03, 0xC0, 1, 2, 3, 4}
+	if got := second.Bytes(); !bytes.Equal(got, want) {
+		t.Errorf("header bytes %x, want %x", got, want)
+	}
+}
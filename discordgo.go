@@ -9,8 +9,10 @@ import (
 
 	"bytes"
 	"errors"
+	"strings"
 	"sync"
 
+	"io"
 	"io/ioutil"
 	"net/http/httputil"
 
@@ -20,8 +22,10 @@ import (
 
 type DiscordBot struct {
 	Guilds            []Guild
+	PrivateChannels   []PrivateChannel
 	HeartbeatInterval int
 	token             string
+	me                BotUser
 	gateway           string
 	ct                *time.Ticker
 	dialer            websocket.Dialer
@@ -29,10 +33,27 @@ type DiscordBot struct {
 	mut               *sync.Mutex
 	isRunning         bool
 	fun               HandleMessage
+	ignoreSelf        bool
+	ignoreBots        bool
+	everyonePolicy    MentionPolicy
+	rolePolicy        MentionPolicy
 	eventFuncs        map[string]EventFunction
+	typedFuncs        map[string]interface{}
+	stateMut          *sync.RWMutex
+	memberMuts        map[string]*memberMutex
+	memberWaiters     map[string]chan struct{}
+	memberSeqs        map[string]int
+	readyGuilds       map[string]bool
+	pendingRoles      map[string]Role
 	rest              *restcl.Rest
 }
 
+//time AddMemberRole and RemoveMemberRole wait for the gateway to confirm the change
+const memberUpdateTimeout = 10 * time.Second
+
+//typing notifications expire after about 10 seconds, so TypingWhile renews them before that
+const typingInterval = 5 * time.Second
+
 type EventFunction func(*DiscordBot)
 type HandleMessage func(MessageResponse, *DiscordBot)
 
@@ -42,8 +63,15 @@ func NewDiscordBot() *DiscordBot {
 			InsecureSkipVerify: true,
 			ServerName:         "discord.gg",
 		}},
-		eventFuncs: make(map[string]EventFunction),
-		mut:        &sync.Mutex{},
+		eventFuncs:    make(map[string]EventFunction),
+		typedFuncs:    make(map[string]interface{}),
+		stateMut:      &sync.RWMutex{},
+		memberMuts:    make(map[string]*memberMutex),
+		memberWaiters: make(map[string]chan struct{}),
+		memberSeqs:    make(map[string]int),
+		readyGuilds:   make(map[string]bool),
+		pendingRoles:  make(map[string]Role),
+		mut:           &sync.Mutex{},
 	}
 
 	rest := restcl.NewRest()
@@ -51,9 +79,38 @@ func NewDiscordBot() *DiscordBot {
 	rest.Create("/auth/login").SetMethod("POST").Build("login")
 	rest.Create("/gateway").SetMethod("GET").Build("gateway")
 	rest.Create("/channels/{channelid}/messages").SetMethod("POST").Build("sendmessage")
+	rest.Create("/channels/{channelid}/pins/{messageid}").SetMethod("PUT").Build("pinmessage")
+	rest.Create("/channels/{channelid}/pins/{messageid}").SetMethod("DELETE").Build("unpinmessage")
+	rest.Create("/channels/{channelid}/pins").SetMethod("GET").Build("getpins")
+	rest.Create("/channels/{channelid}/typing").SetMethod("POST").Build("typing")
+	rest.Create("/users/@me/channels").SetMethod("POST").Build("createdm")
+	rest.Create("/users/@me").SetMethod("PATCH").Build("editprofile")
 	rest.Create("/guilds/{guildid}/members/{userid}").SetMethod("PATCH").Build("changerole")
+	rest.Create("/guilds/{guildid}/members/{userid}").SetMethod("DELETE").Build("kickmember")
+	rest.Create("/guilds/{guildid}/roles").SetMethod("POST").Build("createrole")
+	rest.Create("/guilds/{guildid}/roles/{roleid}").SetMethod("PATCH").Build("editrole")
+	rest.Create("/guilds/{guildid}/roles/{roleid}").SetMethod("DELETE").Build("deleterole")
+	rest.Create("/guilds/{guildid}/roles").SetMethod("PATCH").Build("reorderroles")
+	rest.Create("/guilds/{guildid}/bans/{userid}?delete-message-days={days}").SetMethod("PUT").Build("banmember")
+	rest.Create("/guilds/{guildid}/bans/{userid}").SetMethod("DELETE").Build("unbanmember")
+	rest.Create("/guilds/{guildid}/bans").SetMethod("GET").Build("getbans")
 	rest.Create("/channels/{channelid}").SetMethod("PATCH").Build("changechannelinfo")
+	rest.Create("/guilds/{guildid}/channels").SetMethod("POST").Build("createchannel")
+	rest.Create("/channels/{channelid}").SetMethod("DELETE").Build("deletechannel")
+	rest.Create("/channels/{channelid}/permissions/{overwriteid}").SetMethod("PUT").Build("editpermission")
+	rest.Create("/channels/{channelid}/permissions/{overwriteid}").SetMethod("DELETE").Build("deletepermission")
 	rest.Create("/guilds/{guildid}").SetMethod("PATCH").Build("changeserverinfo")
+	rest.Create("/guilds").SetMethod("POST").Build("createguild")
+	rest.Create("/guilds/{guildid}").SetMethod("GET").Build("getguild")
+	rest.Create("/guilds/{guildid}").SetMethod("DELETE").Build("deleteguild")
+	rest.Create("/guilds/{guildid}/channels").SetMethod("GET").Build("getguildchannels")
+	rest.Create("/users/@me/guilds").SetMethod("GET").Build("getuserguilds")
+	rest.Create("/users/@me/guilds/{guildid}").SetMethod("DELETE").Build("leaveguild")
+	rest.Create("/channels/{channelid}/invites").SetMethod("POST").Build("createinvite")
+	rest.Create("/guilds/{guildid}/invites").SetMethod("GET").Build("getguildinvites")
+	rest.Create("/invite/{code}").SetMethod("GET").Build("getinvite")
+	rest.Create("/invite/{code}").SetMethod("DELETE").Build("deleteinvite")
+	rest.Create("/invite/{code}").SetMethod("POST").Build("acceptinvite")
 	d.rest = rest
 	return d
 }
@@ -63,7 +120,51 @@ func (d *DiscordBot) modify(req *http.Request) {
 	req.Header.Add("authorization", d.token)
 }
 
+//Me returns the user the bot is logged in as, it is empty until READY was received
+func (d *DiscordBot) Me() BotUser {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	return d.me
+}
+
+func (d *DiscordBot) setMe(user BotUser) {
+	d.mut.Lock()
+	d.me = user
+	d.mut.Unlock()
+}
+
 func (d *DiscordBot) GetGuildById(id string) (guild Guild, index int) {
+	d.stateMut.RLock()
+	defer d.stateMut.RUnlock()
+	return d.guildById(id)
+}
+
+func (d *DiscordBot) GetChannelById(id string, guild Guild) (channel Channel, index int) {
+	d.stateMut.RLock()
+	defer d.stateMut.RUnlock()
+	return d.channelById(id, guild)
+}
+
+func (d *DiscordBot) GetMemberById(id string, guild Guild) (member Member, index int) {
+	d.stateMut.RLock()
+	defer d.stateMut.RUnlock()
+	return d.memberById(id, guild)
+}
+
+func (d *DiscordBot) GetPrivateChannelById(id string) (channel PrivateChannel, index int) {
+	d.stateMut.RLock()
+	defer d.stateMut.RUnlock()
+	return d.privateChannelById(id)
+}
+
+func (d *DiscordBot) GetPrivateChannelByUserId(userid string) (channel PrivateChannel, index int) {
+	d.stateMut.RLock()
+	defer d.stateMut.RUnlock()
+	return d.privateChannelByUserId(userid)
+}
+
+//the unexported lookups expect the caller to hold stateMut, every read and write of the cache goes through it
+func (d *DiscordBot) guildById(id string) (guild Guild, index int) {
 	for idx, guild1 := range d.Guilds {
 		if guild1.ID == id {
 			guild = guild1
@@ -74,7 +175,7 @@ func (d *DiscordBot) GetGuildById(id string) (guild Guild, index int) {
 	return
 }
 
-func (d *DiscordBot) GetChannelById(id string, guild Guild) (channel Channel, index int) {
+func (d *DiscordBot) channelById(id string, guild Guild) (channel Channel, index int) {
 	for idx, channel1 := range guild.Channels {
 		if channel1.ID == id {
 			channel = channel1
@@ -85,8 +186,43 @@ func (d *DiscordBot) GetChannelById(id string, guild Guild) (channel Channel, in
 	return
 }
 
+func (d *DiscordBot) memberById(id string, guild Guild) (member Member, index int) {
+	for idx, member1 := range guild.Members {
+		if member1.User.ID == id {
+			member = member1
+			index = idx
+			break
+		}
+	}
+	return
+}
+
+func (d *DiscordBot) privateChannelById(id string) (channel PrivateChannel, index int) {
+	for idx, channel1 := range d.PrivateChannels {
+		if channel1.ID == id {
+			channel = channel1
+			index = idx
+			break
+		}
+	}
+	return
+}
+
+func (d *DiscordBot) privateChannelByUserId(userid string) (channel PrivateChannel, index int) {
+	for idx, channel1 := range d.PrivateChannels {
+		if channel1.Recipient.ID == userid {
+			channel = channel1
+			index = idx
+			break
+		}
+	}
+	return
+}
+
 func (d *DiscordBot) updatePresence(msg dPUMessage) {
-	guild, index := d.GetGuildById(msg.D.GuildID)
+	d.stateMut.Lock()
+	defer d.stateMut.Unlock()
+	guild, index := d.guildById(msg.D.GuildID)
 	if guild.ID != "" {
 		var index2 int
 		var presence Presence
@@ -106,25 +242,51 @@ func (d *DiscordBot) updatePresence(msg dPUMessage) {
 }
 
 func (d *DiscordBot) updateMemberFromGuild(msg dGMUMessage) {
-	guild, index := d.GetGuildById(msg.D.GuildID)
+	key := msg.D.GuildID + ":" + msg.D.User.ID
+	d.stateMut.Lock()
+	//events are handled concurrently, so an older update may arrive after a newer one
+	if msg.S < d.memberSeqs[key] {
+		d.stateMut.Unlock()
+		return
+	}
+	d.memberSeqs[key] = msg.S
+	guild, index := d.guildById(msg.D.GuildID)
 	if guild.ID != "" {
-		var index2 int
-		var umember Member
-		for idx, member := range guild.Members {
-			if member.User.ID == msg.D.User.ID {
-				umember = member
-				umember.Roles = msg.D.Roles
-				index2 = idx
-				break
-			}
+		umember, index2 := d.memberById(msg.D.User.ID, guild)
+		if umember.User.ID != "" {
+			umember.Roles = msg.D.Roles
+			guild.Members[index2] = umember
+			d.Guilds[index] = guild
+		}
+	}
+	d.stateMut.Unlock()
+
+	d.mut.Lock()
+	waiter, exists := d.memberWaiters[key]
+	d.mut.Unlock()
+	if exists {
+		//only a wake up, the waiting call reads the roles from the cache so a pending one is enough
+		select {
+		case waiter <- struct{}{}:
+		default:
 		}
-		guild.Members[index2] = umember
-		d.Guilds[index] = guild
 	}
 }
 
+//returns the cached roles of the member, exists is false if the member is not cached
+func (d *DiscordBot) cachedMemberRoles(guildid string, userid string) (roles []string, exists bool) {
+	d.stateMut.RLock()
+	defer d.stateMut.RUnlock()
+	guild, _ := d.guildById(guildid)
+	member, _ := d.memberById(userid, guild)
+	return append([]string(nil), member.Roles...), member.User.ID != ""
+}
+
 func (d *DiscordBot) removeMemberFromGuild(user User, guildid string) {
-	guild, index := d.GetGuildById(guildid)
+	d.stateMut.Lock()
+	defer d.stateMut.Unlock()
+	delete(d.memberSeqs, guildid+":"+user.ID)
+	guild, index := d.guildById(guildid)
 	if guild.ID != "" {
 		var members []Member
 		for _, member := range guild.Members {
@@ -138,7 +300,9 @@ func (d *DiscordBot) removeMemberFromGuild(user User, guildid string) {
 }
 
 func (d *DiscordBot) addMemberToGuild(msg dGMAMessage) {
-	guild, index := d.GetGuildById(msg.D.GuildID)
+	d.stateMut.Lock()
+	defer d.stateMut.Unlock()
+	guild, index := d.guildById(msg.D.GuildID)
 	if guild.ID != "" {
 		member := Member{}
 		member.User = msg.D.User
@@ -151,22 +315,219 @@ func (d *DiscordBot) addMemberToGuild(msg dGMAMessage) {
 	}
 }
 
-func (d *DiscordBot) updateChannel(msg dCUMessage) {
-	guild, gindex := d.GetGuildById(msg.D.GuildID)
+func (d *DiscordBot) updateChannel(msg dCMessage) {
+	d.stateMut.Lock()
+	defer d.stateMut.Unlock()
+	guild, gindex := d.guildById(msg.D.GuildID)
+	if guild.ID != "" {
+		channel, cindex := d.channelById(msg.D.ID, guild)
+		if channel.ID != "" {
+			guild.Channels[cindex] = msg.D
+			d.Guilds[gindex] = guild
+		}
+	}
+}
+
+func (d *DiscordBot) addChannelToGuild(msg dCMessage) {
+	d.stateMut.Lock()
+	defer d.stateMut.Unlock()
+	guild, gindex := d.guildById(msg.D.GuildID)
 	if guild.ID != "" {
-		channel, cindex := d.GetChannelById(msg.D.ID, guild)
-		if channel.Name != "" {
-			channel.Name = msg.D.Name
-			channel.Position = msg.D.Position
-			channel.Topic = msg.D.Topic
-			guild.Channels[cindex] = channel
+		channel, _ := d.channelById(msg.D.ID, guild)
+		if channel.ID == "" {
+			guild.Channels = append(guild.Channels, msg.D)
 			d.Guilds[gindex] = guild
 		}
 	}
 }
 
+func (d *DiscordBot) removeChannelFromGuild(msg dCMessage) {
+	d.stateMut.Lock()
+	defer d.stateMut.Unlock()
+	guild, gindex := d.guildById(msg.D.GuildID)
+	if guild.ID != "" {
+		var channels []Channel
+		for _, channel := range guild.Channels {
+			if channel.ID != msg.D.ID {
+				channels = append(channels, channel)
+			}
+		}
+		guild.Channels = channels
+		d.Guilds[gindex] = guild
+	}
+}
+
+func (d *DiscordBot) addPrivateChannel(pchannel PrivateChannel) {
+	d.stateMut.Lock()
+	defer d.stateMut.Unlock()
+	channel, _ := d.privateChannelById(pchannel.ID)
+	if channel.ID == "" {
+		d.PrivateChannels = append(d.PrivateChannels, pchannel)
+	}
+}
+
+func (d *DiscordBot) removePrivateChannel(channelid string) {
+	d.stateMut.Lock()
+	defer d.stateMut.Unlock()
+	var channels []PrivateChannel
+	for _, channel := range d.PrivateChannels {
+		if channel.ID != channelid {
+			channels = append(channels, channel)
+		}
+	}
+	d.PrivateChannels = channels
+}
+
+//resolveChannel reports whether the message was sent in a private channel, known is false if the channel
+//is not cached. Such a message is not treated as private since it may be from a guild channel
+func (d *DiscordBot) resolveChannel(message Message) (private bool, known bool) {
+	d.stateMut.RLock()
+	defer d.stateMut.RUnlock()
+	if pchannel, _ := d.privateChannelById(message.ChannelID); pchannel.ID != "" {
+		return true, true
+	}
+	if guild, _ := d.guildByChannelId(message.ChannelID); guild.ID != "" || message.GuildID != "" {
+		return false, true
+	}
+	return false, false
+}
+
+func (d *DiscordBot) updateRole(guildid string, role Role) (change RoleChange) {
+	d.stateMut.Lock()
+	defer d.stateMut.Unlock()
+	change.GuildID = guildid
+	change.New = role
+	guild, gindex := d.guildById(guildid)
+	if guild.ID != "" {
+		for idx, crole := range guild.Roles {
+			if crole.ID == role.ID {
+				change.Old = crole
+				guild.Roles[idx] = role
+				d.Guilds[gindex] = guild
+				return
+			}
+		}
+		guild.Roles = append(guild.Roles, role)
+		d.Guilds[gindex] = guild
+	}
+	return
+}
+
+//CreateRole, EditRole, DeleteRole and ReorderRoles write their result to the cache right away. The gateway
+//echo of such a change would then find the cache already changed, so the role as it was before the local
+//change is remembered and used as Old when the echo arrives.
+func (d *DiscordBot) rememberPendingRole(change RoleChange, roleid string) {
+	if change.Old == change.New {
+		//the echo already arrived or nothing changed
+		return
+	}
+	d.mut.Lock()
+	d.pendingRoles[change.GuildID+":"+roleid] = change.Old
+	d.mut.Unlock()
+}
+
+//restorePendingRole replaces Old with the remembered role if the change is the echo of a local change
+func (d *DiscordBot) restorePendingRole(change RoleChange, roleid string) RoleChange {
+	key := change.GuildID + ":" + roleid
+	d.mut.Lock()
+	old, exists := d.pendingRoles[key]
+	delete(d.pendingRoles, key)
+	d.mut.Unlock()
+	if exists {
+		change.Old = old
+	}
+	return change
+}
+
+func (d *DiscordBot) removeRoleFromGuild(guildid string, roleid string) (change RoleChange) {
+	d.stateMut.Lock()
+	defer d.stateMut.Unlock()
+	change.GuildID = guildid
+	guild, gindex := d.guildById(guildid)
+	if guild.ID != "" {
+		var roles []Role
+		for _, role := range guild.Roles {
+			if role.ID == roleid {
+				change.Old = role
+			} else {
+				roles = append(roles, role)
+			}
+		}
+		guild.Roles = roles
+		for midx, member := range guild.Members {
+			var mroles []string
+			for _, id := range member.Roles {
+				if id != roleid {
+					mroles = append(mroles, id)
+				}
+			}
+			guild.Members[midx].Roles = mroles
+		}
+		d.Guilds[gindex] = guild
+	}
+	return
+}
+
+//adds the guild to the cache or replaces the cached guild with the same id and returns the guild cached before.
+//READY and GUILD_CREATE are handled concurrently, so the unavailable stub from READY never replaces an available guild
+func (d *DiscordBot) setGuild(guild Guild) (cached Guild) {
+	d.stateMut.Lock()
+	defer d.stateMut.Unlock()
+	for i := range guild.VoiceStates {
+		guild.VoiceStates[i].GuildID = guild.ID
+	}
+	for i := range guild.Channels {
+		guild.Channels[i].GuildID = guild.ID
+	}
+	cached, index := d.guildById(guild.ID)
+	if cached.ID == "" {
+		d.Guilds = append(d.Guilds, guild)
+	} else if !guild.Unavailable || cached.Unavailable {
+		d.Guilds[index] = guild
+	}
+	return
+}
+
+//marks the cached guild as unavailable and returns it, the cached data is kept until the guild is available again
+func (d *DiscordBot) markGuildUnavailable(guildid string) (guild Guild) {
+	d.stateMut.Lock()
+	defer d.stateMut.Unlock()
+	guild, index := d.guildById(guildid)
+	if guild.ID != "" {
+		guild.Unavailable = true
+		d.Guilds[index] = guild
+	} else {
+		guild = Guild{ID: guildid, Unavailable: true}
+		d.Guilds = append(d.Guilds, guild)
+	}
+	return
+}
+
+//removes the guild from the cache and returns the last cached version
+func (d *DiscordBot) removeGuild(guildid string) (removed Guild) {
+	d.stateMut.Lock()
+	defer d.stateMut.Unlock()
+	for key := range d.memberSeqs {
+		if strings.HasPrefix(key, guildid+":") {
+			delete(d.memberSeqs, key)
+		}
+	}
+	var guilds []Guild
+	for _, guild := range d.Guilds {
+		if guild.ID == guildid {
+			removed = guild
+		} else {
+			guilds = append(guilds, guild)
+		}
+	}
+	d.Guilds = guilds
+	return
+}
+
 func (d *DiscordBot) updateGuild(msg dGUMessage) {
-	guild, gindex := d.GetGuildById(msg.D.ID)
+	d.stateMut.Lock()
+	defer d.stateMut.Unlock()
+	guild, gindex := d.guildById(msg.D.ID)
 	if guild.ID != "" {
 		guild.Roles = msg.D.Roles
 		guild.Region = msg.D.Region
@@ -181,6 +542,8 @@ func (d *DiscordBot) updateGuild(msg dGUMessage) {
 }
 
 func (d *DiscordBot) GetMemberByName(name string) Member {
+	d.stateMut.RLock()
+	defer d.stateMut.RUnlock()
 	for _, guild := range d.Guilds {
 		for _, member := range guild.Members {
 			if member.User.Username == name {
@@ -195,11 +558,80 @@ func (d *DiscordBot) SetHandleFunction(f HandleMessage) {
 	d.fun = f
 }
 
+//SetIgnoreSelf stops messages sent by the bot itself from reaching the HandleMessage function
+func (d *DiscordBot) SetIgnoreSelf(ignore bool) {
+	d.ignoreSelf = ignore
+}
+
+//SetIgnoreBots stops messages sent by any bot, including this one, from reaching the HandleMessage function
+func (d *DiscordBot) SetIgnoreBots(ignore bool) {
+	d.ignoreBots = ignore
+}
+
+//IgnoreSelf wraps a HandleMessage function so it is not called for messages sent by the bot itself
+func IgnoreSelf(f HandleMessage) HandleMessage {
+	return func(message MessageResponse, d *DiscordBot) {
+		if message.D.Author.ID != d.Me().ID {
+			f(message, d)
+		}
+	}
+}
+
+//IgnoreBots wraps a HandleMessage function so it is not called for messages sent by any bot, including this one
+func IgnoreBots(f HandleMessage) HandleMessage {
+	return func(message MessageResponse, d *DiscordBot) {
+		if !message.D.Author.Bot && message.D.Author.ID != d.Me().ID {
+			f(message, d)
+		}
+	}
+}
+
+func (d *DiscordBot) isIgnored(author User) bool {
+	if d.ignoreBots && author.Bot {
+		return true
+	}
+	return (d.ignoreSelf || d.ignoreBots) && author.ID == d.Me().ID
+}
+
+func (d *DiscordBot) SetTypingFunction(f TypingFunction) {
+	d.typedFuncs[EVENT_TYPING_START] = f
+}
+
+func (d *DiscordBot) SetChannelPinsFunction(f ChannelPinsFunction) {
+	d.typedFuncs[EVENT_CHANNEL_PINS_UPDATE] = f
+}
+
 func (d *DiscordBot) AddCallBack(event string, f EventFunction) {
 	//TODO check if event exists and panic if it doesnt
 	d.eventFuncs[event] = f
 }
 
+//AddChannelCallBack registers a function for EVENT_CHANNEL_CREATE, EVENT_CHANNEL_UPDATE or EVENT_CHANNEL_DELETE
+func (d *DiscordBot) AddChannelCallBack(event string, f ChannelFunction) {
+	d.typedFuncs[event] = f
+}
+
+//AddRoleCallBack registers a function for EVENT_GUILD_ROLE_CREATE, EVENT_GUILD_ROLE_UPDATE or EVENT_GUILD_ROLE_DELETE
+func (d *DiscordBot) AddRoleCallBack(event string, f RoleFunction) {
+	d.typedFuncs[event] = f
+}
+
+//AddGuildCallBack registers a function for EVENT_GUILD_CREATE, EVENT_GUILD_DELETE, EVENT_GUILD_AVAILABLE,
+//EVENT_GUILD_UNAVAILABLE or EVENT_GUILD_READY
+func (d *DiscordBot) AddGuildCallBack(event string, f GuildFunction) {
+	d.typedFuncs[event] = f
+}
+
+//AddBanCallBack registers a function for EVENT_GUILD_BAN_ADD or EVENT_GUILD_BAN_REMOVE
+func (d *DiscordBot) AddBanCallBack(event string, f BanFunction) {
+	d.typedFuncs[event] = f
+}
+
+//AddVoiceCallBack registers a function for EVENT_VOICE_STATE_UPDATE, EVENT_VOICE_JOIN, EVENT_VOICE_LEAVE or EVENT_VOICE_MOVE
+func (d *DiscordBot) AddVoiceCallBack(event string, f VoiceStateFunction) {
+	d.typedFuncs[event] = f
+}
+
 func (d *DiscordBot) Login(email string, password string) error {
 	login := loginMessage{
 		Email:    email,
@@ -370,7 +802,9 @@ func (d *DiscordBot) handleMessage(code string, message []byte) {
 		var MessageCreate MessageResponse
 		err := json.Unmarshal(message, &MessageCreate)
 		checkErr(err)
-		if d.fun != nil {
+		private, known := d.resolveChannel(MessageCreate.D)
+		MessageCreate.D.IsPrivate, MessageCreate.D.ChannelUnknown = private, !known
+		if d.fun != nil && !d.isIgnored(MessageCreate.D.Author) {
 			d.fun(MessageCreate, d)
 		}
 		f, exists := d.eventFuncs[EVENT_MESSAGE_CREATE]
@@ -378,15 +812,37 @@ func (d *DiscordBot) handleMessage(code string, message []byte) {
 			f(d)
 		}
 
+	case EVENT_CHANNEL_CREATE:
+		var ChannelCreate dCMessage
+		err := json.Unmarshal(message, &ChannelCreate)
+		checkErr(err)
+		if ChannelCreate.D.IsPrivate {
+			var PrivateChannelCreate dPCMessage
+			err = json.Unmarshal(message, &PrivateChannelCreate)
+			checkErr(err)
+			d.addPrivateChannel(PrivateChannelCreate.D)
+		} else {
+			d.addChannelToGuild(ChannelCreate)
+		}
+		d.dispatchChannel(EVENT_CHANNEL_CREATE, ChannelCreate.D)
+
 	case EVENT_CHANNEL_UPDATE:
-		var ChannelUpdate dCUMessage
+		var ChannelUpdate dCMessage
 		err := json.Unmarshal(message, &ChannelUpdate)
 		checkErr(err)
 		d.updateChannel(ChannelUpdate)
-		f, exists := d.eventFuncs[EVENT_CHANNEL_UPDATE]
-		if exists {
-			f(d)
+		d.dispatchChannel(EVENT_CHANNEL_UPDATE, ChannelUpdate.D)
+
+	case EVENT_CHANNEL_DELETE:
+		var ChannelDelete dCMessage
+		err := json.Unmarshal(message, &ChannelDelete)
+		checkErr(err)
+		if ChannelDelete.D.IsPrivate {
+			d.removePrivateChannel(ChannelDelete.D.ID)
+		} else {
+			d.removeChannelFromGuild(ChannelDelete)
 		}
+		d.dispatchChannel(EVENT_CHANNEL_DELETE, ChannelDelete.D)
 
 	case EVENT_GUILD_UPDATE:
 		var GuildUpdate dGUMessage
@@ -398,6 +854,81 @@ func (d *DiscordBot) handleMessage(code string, message []byte) {
 			f(d)
 		}
 
+	case EVENT_GUILD_ROLE_CREATE, EVENT_GUILD_ROLE_UPDATE:
+		var RoleUpdate dGRMessage
+		err := json.Unmarshal(message, &RoleUpdate)
+		checkErr(err)
+		change := d.updateRole(RoleUpdate.D.GuildID, RoleUpdate.D.Role)
+		d.dispatchRole(code, d.restorePendingRole(change, RoleUpdate.D.Role.ID))
+
+	case EVENT_GUILD_ROLE_DELETE:
+		var RoleDelete dGRMessage
+		err := json.Unmarshal(message, &RoleDelete)
+		checkErr(err)
+		change := d.removeRoleFromGuild(RoleDelete.D.GuildID, RoleDelete.D.RoleID)
+		d.dispatchRole(code, d.restorePendingRole(change, RoleDelete.D.RoleID))
+
+	case EVENT_GUILD_BAN_ADD, EVENT_GUILD_BAN_REMOVE:
+		var BanUpdate dGBMessage
+		err := json.Unmarshal(message, &BanUpdate)
+		checkErr(err)
+		bf, exists := d.typedFuncs[code].(BanFunction)
+		if exists {
+			bf(BanUpdate.D, d)
+		}
+		f, exists := d.eventFuncs[code]
+		if exists {
+			f(d)
+		}
+
+	case EVENT_TYPING_START:
+		var TypingMessage dTSMessage
+		err := json.Unmarshal(message, &TypingMessage)
+		checkErr(err)
+		tf, exists := d.typedFuncs[EVENT_TYPING_START].(TypingFunction)
+		if exists {
+			tf(TypingMessage.D, d)
+		}
+		f, exists := d.eventFuncs[EVENT_TYPING_START]
+		if exists {
+			f(d)
+		}
+
+	case EVENT_USER_UPDATE:
+		var UserUpdate dUUMessage
+		err := json.Unmarshal(message, &UserUpdate)
+		checkErr(err)
+		if UserUpdate.D.ID == d.Me().ID {
+			d.setMe(UserUpdate.D)
+		}
+		f, exists := d.eventFuncs[EVENT_USER_UPDATE]
+		if exists {
+			f(d)
+		}
+
+	case EVENT_CHANNEL_PINS_UPDATE:
+		var PinsUpdate dCPUMessage
+		err := json.Unmarshal(message, &PinsUpdate)
+		checkErr(err)
+		pf, exists := d.typedFuncs[EVENT_CHANNEL_PINS_UPDATE].(ChannelPinsFunction)
+		if exists {
+			pf(PinsUpdate.D, d)
+		}
+		f, exists := d.eventFuncs[EVENT_CHANNEL_PINS_UPDATE]
+		if exists {
+			f(d)
+		}
+
+	case EVENT_VOICE_STATE_UPDATE:
+		var VoiceStateUpdate dVSUMessage
+		err := json.Unmarshal(message, &VoiceStateUpdate)
+		checkErr(err)
+		change := d.updateVoiceState(VoiceStateUpdate.D)
+		d.dispatchVoice(EVENT_VOICE_STATE_UPDATE, change)
+		if event := change.event(); event != "" {
+			d.dispatchVoice(event, change)
+		}
+
 	case EVENT_READY:
 		var ReadyMessage dReadyMessage
 		err := json.Unmarshal(message, &ReadyMessage)
@@ -416,13 +947,103 @@ func (d *DiscordBot) handleMessage(code string, message []byte) {
 				d.conn.WriteMessage(websocket.TextMessage, by)
 			}
 		}()
+		d.setMe(ReadyMessage.D.User)
 		for _, v := range ReadyMessage.D.Guilds {
-			d.Guilds = append(d.Guilds, v)
+			d.setGuild(v)
+		}
+		for _, v := range ReadyMessage.D.PrivateChannels {
+			d.addPrivateChannel(v)
 		}
 		f, exists := d.eventFuncs[EVENT_READY]
 		if exists {
 			f(d)
 		}
+		for _, v := range ReadyMessage.D.Guilds {
+			if !v.Unavailable {
+				d.guildReady(v)
+			}
+		}
+
+	case EVENT_GUILD_CREATE:
+		var GuildCreate dGCMessage
+		err := json.Unmarshal(message, &GuildCreate)
+		checkErr(err)
+		cached := d.setGuild(GuildCreate.D)
+		if cached.ID != "" && cached.Unavailable {
+			d.dispatchGuild(EVENT_GUILD_AVAILABLE, GuildCreate.D)
+		} else {
+			d.dispatchGuild(EVENT_GUILD_CREATE, GuildCreate.D)
+		}
+		d.guildReady(GuildCreate.D)
+
+	case EVENT_GUILD_DELETE:
+		var GuildDelete dGDMessage
+		err := json.Unmarshal(message, &GuildDelete)
+		checkErr(err)
+		if GuildDelete.D.Unavailable {
+			d.dispatchGuild(EVENT_GUILD_UNAVAILABLE, d.markGuildUnavailable(GuildDelete.D.ID))
+		} else {
+			d.mut.Lock()
+			delete(d.readyGuilds, GuildDelete.D.ID)
+			d.mut.Unlock()
+			d.dispatchGuild(EVENT_GUILD_DELETE, d.removeGuild(GuildDelete.D.ID))
+		}
+	}
+}
+
+func (d *DiscordBot) dispatchChannel(event string, channel Channel) {
+	cf, exists := d.typedFuncs[event].(ChannelFunction)
+	if exists {
+		cf(channel, d)
+	}
+	f, exists := d.eventFuncs[event]
+	if exists {
+		f(d)
+	}
+}
+
+func (d *DiscordBot) dispatchRole(event string, change RoleChange) {
+	rf, exists := d.typedFuncs[event].(RoleFunction)
+	if exists {
+		rf(change, d)
+	}
+	f, exists := d.eventFuncs[event]
+	if exists {
+		f(d)
+	}
+}
+
+func (d *DiscordBot) dispatchVoice(event string, change VoiceStateChange) {
+	vf, exists := d.typedFuncs[event].(VoiceStateFunction)
+	if exists {
+		vf(change, d)
+	}
+	f, exists := d.eventFuncs[event]
+	if exists {
+		f(d)
+	}
+}
+
+//guildReady dispatches EVENT_GUILD_READY the first time the guild is fully loaded,
+//a guild coming back from an outage is not ready again, one the bot rejoins after being removed is
+func (d *DiscordBot) guildReady(guild Guild) {
+	d.mut.Lock()
+	ready := d.readyGuilds[guild.ID]
+	d.readyGuilds[guild.ID] = true
+	d.mut.Unlock()
+	if !ready {
+		d.dispatchGuild(EVENT_GUILD_READY, guild)
+	}
+}
+
+func (d *DiscordBot) dispatchGuild(event string, guild Guild) {
+	gf, exists := d.typedFuncs[event].(GuildFunction)
+	if exists {
+		gf(guild, d)
+	}
+	f, exists := d.eventFuncs[event]
+	if exists {
+		f(d)
 	}
 }
 
@@ -444,55 +1065,589 @@ func checkErr(err error) {
 	}
 }
 
+//checkStatus returns an error for a response discord rejected, restcl only reports transport errors
+func checkStatus(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New("request failed with " + resp.Status)
+	}
+	return nil
+}
+
 func makeTimestamp() int64 {
 	return time.Now().UnixNano() / int64(time.Millisecond)
 }
 
+//SendMessage applies the mention policies to the content and sends it,
+//content longer than MESSAGE_LENGTH_LIMIT is sent as several messages in order with the embed on the last one
 func (d *DiscordBot) SendMessage(message MessageRequest, channelid string) (err error) {
-	bmessage, err := json.Marshal(message)
+	if message.Embed != nil {
+		err = message.Embed.Validate()
+		if err != nil {
+			return
+		}
+	}
+	parts := SplitMessage(d.SanitizeContent(message.Content), MESSAGE_LENGTH_LIMIT)
+	for i, content := range parts {
+		part := message
+		part.Content = content
+		if i < len(parts)-1 {
+			part.Embed = nil
+		}
+		bmessage, err := json.Marshal(part)
+		if err != nil {
+			return err
+		}
+		_, err = d.rest.Get("sendmessage").SetParams("channelid", channelid).SetBody(bytes.NewReader(bmessage)).Exec(nil)
+		if err != nil {
+			return err
+		}
+	}
+	return
+}
+
+func (d *DiscordBot) PinMessage(channelid string, messageid string) (err error) {
+	resp, err := d.rest.Get("pinmessage").
+		SetParams("channelid", channelid, "messageid", messageid).
+		Exec(nil)
+	return checkStatus(resp, err)
+}
+
+func (d *DiscordBot) UnpinMessage(channelid string, messageid string) (err error) {
+	resp, err := d.rest.Get("unpinmessage").
+		SetParams("channelid", channelid, "messageid", messageid).
+		Exec(nil)
+	return checkStatus(resp, err)
+}
+
+func (d *DiscordBot) GetPinnedMessages(channelid string) (messages []Message, err error) {
+	resp, err := d.rest.Get("getpins").
+		SetParams("channelid", channelid).
+		Exec(&messages)
+	err = checkStatus(resp, err)
+	return
+}
+
+//StartTyping shows the bot as typing in the channel for a few seconds or until it sends a message
+func (d *DiscordBot) StartTyping(channelid string) (err error) {
+	resp, err := d.rest.Get("typing").
+		SetParams("channelid", channelid).
+		Exec(nil)
+	return checkStatus(resp, err)
+}
+
+//TypingWhile shows the bot as typing in the channel until f returns
+func (d *DiscordBot) TypingWhile(channelid string, f func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(typingInterval)
+		defer ticker.Stop()
+		for {
+			checkErr(d.StartTyping(channelid))
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+	defer close(done)
+	f()
+}
+
+//EditProfile changes the username and avatar of the bot, an empty username or nil avatar keeps the current one
+func (d *DiscordBot) EditProfile(username string, avatar io.Reader) (user BotUser, err error) {
+	ma := map[string]interface{}{}
+	if username != "" {
+		ma["username"] = username
+	}
+	if avatar != nil {
+		ma["avatar"], err = EncodeImage(avatar)
+		if err != nil {
+			return
+		}
+	}
+	bmessage, err := json.Marshal(ma)
 	if err != nil {
 		return
 	}
+	resp, err := d.rest.Get("editprofile").
+		SetBody(bytes.NewReader(bmessage)).
+		Exec(&user)
+	if err = checkStatus(resp, err); err == nil && user.ID != "" {
+		d.setMe(user)
+	}
+	return
+}
 
-	_, err = d.rest.Get("sendmessage").SetParams("channelid", channelid).SetBody(bytes.NewReader(bmessage)).Exec(nil)
+//CreateDM returns the cached private channel with the user or opens a new one
+func (d *DiscordBot) CreateDM(userid string) (channel PrivateChannel, err error) {
+	channel, _ = d.GetPrivateChannelByUserId(userid)
+	if channel.ID != "" {
+		return
+	}
+	bmessage, err := json.Marshal(map[string]interface{}{
+		"recipient_id": userid,
+	})
+	if err != nil {
+		return
+	}
+	resp, err := d.rest.Get("createdm").SetBody(bytes.NewReader(bmessage)).Exec(&channel)
+	if err = checkStatus(resp, err); err != nil {
+		return
+	}
+	if channel.ID == "" {
+		return channel, errors.New("could not open private channel with " + userid)
+	}
+	d.addPrivateChannel(channel)
 	return
 }
 
+func (d *DiscordBot) SendDirectMessage(userid string, message MessageRequest) (err error) {
+	channel, err := d.CreateDM(userid)
+	if err != nil {
+		return
+	}
+	return d.SendMessage(message, channel.ID)
+}
+
 func (d *DiscordBot) ChangeRolesForUser(user Member, guildid string) (err error) {
-	ma := map[string]interface{}{
+	return d.patchMember(guildid, user.User.ID, map[string]interface{}{
 		"roles": user.Roles,
+	})
+}
+
+//AddMemberRole adds the role to the cached roles of the member and waits for the GUILD_MEMBER_UPDATE confirming it
+func (d *DiscordBot) AddMemberRole(guildid string, userid string, roleid string) error {
+	return d.changeMemberRole(guildid, userid, roleid, true)
+}
+
+//RemoveMemberRole removes the role from the cached roles of the member and waits for the GUILD_MEMBER_UPDATE confirming it
+func (d *DiscordBot) RemoveMemberRole(guildid string, userid string, roleid string) error {
+	return d.changeMemberRole(guildid, userid, roleid, false)
+}
+
+func (d *DiscordBot) changeMemberRole(guildid string, userid string, roleid string, add bool) (err error) {
+	key := guildid + ":" + userid
+
+	//serialize role changes per member, otherwise concurrent calls would read the same cached roles
+	mmut := d.lockMember(key)
+	defer d.unlockMember(key, mmut)
+
+	current, exists := d.cachedMemberRoles(guildid, userid)
+	if !exists {
+		return errors.New("member not found in guild " + guildid)
 	}
-	bmessage, err := json.Marshal(ma)
+	if hasRole(current, roleid) == add {
+		return nil
+	}
+
+	var roles []string
+	for _, id := range current {
+		if id != roleid {
+			roles = append(roles, id)
+		}
+	}
+	if add {
+		roles = append(roles, roleid)
+	}
+
+	waiter := make(chan struct{}, 1)
+	d.mut.Lock()
+	d.memberWaiters[key] = waiter
+	d.mut.Unlock()
+	defer func() {
+		d.mut.Lock()
+		delete(d.memberWaiters, key)
+		d.mut.Unlock()
+	}()
+
+	err = d.ChangeRolesForUser(Member{User: User{ID: userid}, Roles: roles}, guildid)
+	if err != nil {
+		return
+	}
+
+	//other updates of the member may arrive first, so check the cache after every update until it contains the change
+	timeout := time.After(memberUpdateTimeout)
+	for {
+		select {
+		case <-waiter:
+		case <-timeout:
+			if updated, _ := d.cachedMemberRoles(guildid, userid); hasRole(updated, roleid) == add {
+				return nil
+			}
+			return errors.New("role change of member " + userid + " was not confirmed by a GUILD_MEMBER_UPDATE")
+		}
+		if updated, _ := d.cachedMemberRoles(guildid, userid); hasRole(updated, roleid) == add {
+			return nil
+		}
+	}
+}
+
+//memberMutex is removed from memberMuts once no call holds or waits for it anymore
+type memberMutex struct {
+	sync.Mutex
+	refs int
+}
+
+func (d *DiscordBot) lockMember(key string) *memberMutex {
+	d.mut.Lock()
+	mmut, exists := d.memberMuts[key]
+	if !exists {
+		mmut = &memberMutex{}
+		d.memberMuts[key] = mmut
+	}
+	mmut.refs++
+	d.mut.Unlock()
+	mmut.Lock()
+	return mmut
+}
+
+func (d *DiscordBot) unlockMember(key string, mmut *memberMutex) {
+	mmut.Unlock()
+	d.mut.Lock()
+	mmut.refs--
+	if mmut.refs == 0 {
+		delete(d.memberMuts, key)
+	}
+	d.mut.Unlock()
+}
+
+func hasRole(roles []string, roleid string) bool {
+	for _, id := range roles {
+		if id == roleid {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *DiscordBot) ServerMuteMember(guildid string, userid string, mute bool) (err error) {
+	return d.patchMember(guildid, userid, map[string]interface{}{
+		"mute": mute,
+	})
+}
+
+func (d *DiscordBot) ServerDeafenMember(guildid string, userid string, deaf bool) (err error) {
+	return d.patchMember(guildid, userid, map[string]interface{}{
+		"deaf": deaf,
+	})
+}
+
+//MoveMember moves a member who is connected to voice to another voice channel of the guild
+func (d *DiscordBot) MoveMember(guildid string, userid string, channelid string) (err error) {
+	return d.patchMember(guildid, userid, map[string]interface{}{
+		"channel_id": channelid,
+	})
+}
+
+//patchMember only sends the given fields so other member fields like the roles stay untouched
+func (d *DiscordBot) patchMember(guildid string, userid string, fields map[string]interface{}) (err error) {
+	bmessage, err := json.Marshal(fields)
 	if err != nil {
 		return
 	}
-	_, err = d.rest.Get("changerole").SetParams("guildid", guildid, "userid", user.User.ID).
+	resp, err := d.rest.Get("changerole").SetParams("guildid", guildid, "userid", userid).
 		SetBody(bytes.NewReader(bmessage)).Exec(nil)
+	return checkStatus(resp, err)
+}
 
-	return
+func (d *DiscordBot) KickMember(guildid string, userid string) (err error) {
+	resp, err := d.rest.Get("kickmember").
+		SetParams("guildid", guildid, "userid", userid).
+		Exec(nil)
+	return checkStatus(resp, err)
 }
 
-func (d *DiscordBot) ChangeChannelInformation(channelupdate ChannelUpdateRequest, channelid string) (err error) {
+func (d *DiscordBot) ChangeChannelInformation(channelupdate *ChannelUpdateRequest, channelid string) (err error) {
 	bmessage, err := json.Marshal(channelupdate)
 	if err != nil {
 		return
 	}
-	_, err = d.rest.Get("changechannelinfo").
+	resp, err := d.rest.Get("changechannelinfo").
 		SetParams("channelid", channelid).
 		SetBody(bytes.NewReader(bmessage)).
 		Exec(nil)
+	return checkStatus(resp, err)
+}
+
+//CreateChannel creates a channel in the guild, channeltype is CHANNEL_TYPE_TEXT or CHANNEL_TYPE_VOICE
+func (d *DiscordBot) CreateChannel(guildid string, name string, channeltype string) (channel Channel, err error) {
+	bmessage, err := json.Marshal(map[string]interface{}{
+		"name": name,
+		"type": channeltype,
+	})
+	if err != nil {
+		return
+	}
+	resp, err := d.rest.Get("createchannel").
+		SetParams("guildid", guildid).
+		SetBody(bytes.NewReader(bmessage)).
+		Exec(&channel)
+	err = checkStatus(resp, err)
 	return
 }
 
-func (d *DiscordBot) ChangeServerInformation(serverupdate ServerUpdateRequest, guildid string) (err error) {
+func (d *DiscordBot) DeleteChannel(channelid string) (err error) {
+	resp, err := d.rest.Get("deletechannel").
+		SetParams("channelid", channelid).
+		Exec(nil)
+	return checkStatus(resp, err)
+}
+
+//EditPermissionOverwrite creates or replaces the overwrite with the same id in the channel
+func (d *DiscordBot) EditPermissionOverwrite(channelid string, overwrite PermissionOverwrite) (err error) {
+	bmessage, err := json.Marshal(overwrite)
+	if err != nil {
+		return
+	}
+	resp, err := d.rest.Get("editpermission").
+		SetParams("channelid", channelid, "overwriteid", overwrite.ID).
+		SetBody(bytes.NewReader(bmessage)).
+		Exec(nil)
+	return checkStatus(resp, err)
+}
+
+func (d *DiscordBot) DeletePermissionOverwrite(channelid string, overwriteid string) (err error) {
+	resp, err := d.rest.Get("deletepermission").
+		SetParams("channelid", channelid, "overwriteid", overwriteid).
+		Exec(nil)
+	return checkStatus(resp, err)
+}
+
+func (d *DiscordBot) ChangeServerInformation(serverupdate *ServerUpdateRequest, guildid string) (err error) {
 	bmessage, err := json.Marshal(serverupdate)
 	if err != nil {
 		return
 	}
-	_, err = d.rest.Get("changeserverinfo").
+	resp, err := d.rest.Get("changeserverinfo").
 		SetParams("guildid", guildid).
 		SetBody(bytes.NewReader(bmessage)).
 		Exec(nil)
+	return checkStatus(resp, err)
+}
+
+//CreateRole creates a role in the guild and adds it to the cached roles
+func (d *DiscordBot) CreateRole(guildid string, name string, color int, hoist bool, permissions Permissions) (role Role, err error) {
+	bmessage, err := json.Marshal(map[string]interface{}{
+		"name":        name,
+		"color":       color,
+		"hoist":       hoist,
+		"permissions": permissions,
+	})
+	if err != nil {
+		return
+	}
+	resp, err := d.rest.Get("createrole").
+		SetParams("guildid", guildid).
+		SetBody(bytes.NewReader(bmessage)).
+		Exec(&role)
+	if err = checkStatus(resp, err); err == nil && role.ID != "" {
+		d.rememberPendingRole(d.updateRole(guildid, role), role.ID)
+	}
+	return
+}
+
+//EditRole changes the role and replaces it in the cached roles
+func (d *DiscordBot) EditRole(guildid string, roleid string, name string, color int, hoist bool, permissions Permissions) (role Role, err error) {
+	bmessage, err := json.Marshal(map[string]interface{}{
+		"name":        name,
+		"color":       color,
+		"hoist":       hoist,
+		"permissions": permissions,
+	})
+	if err != nil {
+		return
+	}
+	resp, err := d.rest.Get("editrole").
+		SetParams("guildid", guildid, "roleid", roleid).
+		SetBody(bytes.NewReader(bmessage)).
+		Exec(&role)
+	if err = checkStatus(resp, err); err == nil && role.ID != "" {
+		d.rememberPendingRole(d.updateRole(guildid, role), role.ID)
+	}
+	return
+}
+
+//DeleteRole deletes the role and removes it from the cached roles and members
+func (d *DiscordBot) DeleteRole(guildid string, roleid string) (err error) {
+	resp, err := d.rest.Get("deleterole").
+		SetParams("guildid", guildid, "roleid", roleid).
+		Exec(nil)
+	if err = checkStatus(resp, err); err == nil {
+		d.rememberPendingRole(d.removeRoleFromGuild(guildid, roleid), roleid)
+	}
+	return
+}
+
+//ReorderRoles sets the position of every given role to its Position field and returns the updated roles
+func (d *DiscordBot) ReorderRoles(guildid string, roles []Role) (updated []Role, err error) {
+	var positions []map[string]interface{}
+	for _, role := range roles {
+		positions = append(positions, map[string]interface{}{
+			"id":       role.ID,
+			"position": role.Position,
+		})
+	}
+	bmessage, err := json.Marshal(positions)
+	if err != nil {
+		return
+	}
+	resp, err := d.rest.Get("reorderroles").
+		SetParams("guildid", guildid).
+		SetBody(bytes.NewReader(bmessage)).
+		Exec(&updated)
+	if err = checkStatus(resp, err); err == nil {
+		for _, role := range updated {
+			d.rememberPendingRole(d.updateRole(guildid, role), role.ID)
+		}
+	}
+	return
+}
+
+//BanMember bans the user from the guild and deletes the messages of the last deleteMessageDays days (0-7)
+func (d *DiscordBot) BanMember(guildid string, userid string, deleteMessageDays int) (err error) {
+	resp, err := d.rest.Get("banmember").
+		SetParams("guildid", guildid, "userid", userid, "days", deleteMessageDays).
+		Exec(nil)
+	return checkStatus(resp, err)
+}
+
+func (d *DiscordBot) UnbanMember(guildid string, userid string) (err error) {
+	resp, err := d.rest.Get("unbanmember").
+		SetParams("guildid", guildid, "userid", userid).
+		Exec(nil)
+	return checkStatus(resp, err)
+}
+
+func (d *DiscordBot) GetBans(guildid string) (bans []Ban, err error) {
+	resp, err := d.rest.Get("getbans").
+		SetParams("guildid", guildid).
+		Exec(&bans)
+	err = checkStatus(resp, err)
+	return
+}
+
+//CreateGuild creates a guild owned by the bot, icon is a data uri or empty
+func (d *DiscordBot) CreateGuild(name string, region string, icon string) (guild Guild, err error) {
+	ma := map[string]interface{}{
+		"name":   name,
+		"region": region,
+	}
+	if icon != "" {
+		ma["icon"] = icon
+	}
+	bmessage, err := json.Marshal(ma)
+	if err != nil {
+		return
+	}
+	resp, err := d.rest.Get("createguild").
+		SetBody(bytes.NewReader(bmessage)).
+		Exec(&guild)
+	err = checkStatus(resp, err)
+	return
+}
+
+//DeleteGuild deletes a guild owned by the bot
+func (d *DiscordBot) DeleteGuild(guildid string) (err error) {
+	resp, err := d.rest.Get("deleteguild").
+		SetParams("guildid", guildid).
+		Exec(nil)
+	return checkStatus(resp, err)
+}
+
+func (d *DiscordBot) LeaveGuild(guildid string) (err error) {
+	resp, err := d.rest.Get("leaveguild").
+		SetParams("guildid", guildid).
+		Exec(nil)
+	return checkStatus(resp, err)
+}
+
+func (d *DiscordBot) GetUserGuilds() (guilds []UserGuild, err error) {
+	resp, err := d.rest.Get("getuserguilds").Exec(&guilds)
+	err = checkStatus(resp, err)
+	return
+}
+
+//GetGuild requests the guild and its channels and replaces them in the cache,
+//members, presences and voice states are only sent over the gateway and are kept from the cache
+func (d *DiscordBot) GetGuild(guildid string) (guild Guild, err error) {
+	resp, err := d.rest.Get("getguild").
+		SetParams("guildid", guildid).
+		Exec(&guild)
+	if err = checkStatus(resp, err); err != nil {
+		return
+	}
+	if guild.ID == "" {
+		return guild, errors.New("guild not found " + guildid)
+	}
+	resp, err = d.rest.Get("getguildchannels").
+		SetParams("guildid", guildid).
+		Exec(&guild.Channels)
+	if err = checkStatus(resp, err); err != nil {
+		return
+	}
+	cached, _ := d.GetGuildById(guildid)
+	if cached.ID != "" {
+		guild.Members = cached.Members
+		guild.Presences = cached.Presences
+		guild.VoiceStates = cached.VoiceStates
+		guild.JoinedAt = cached.JoinedAt
+	}
+	d.setGuild(guild)
+	return
+}
+
+//CreateInvite creates an invite for the channel, a maxAge (in seconds) or maxUses of 0 means unlimited,
+//temporary invites kick the member again once they disconnect unless a role was assigned
+func (d *DiscordBot) CreateInvite(channelid string, maxAge int, maxUses int, temporary bool) (invite Invite, err error) {
+	bmessage, err := json.Marshal(map[string]interface{}{
+		"max_age":   maxAge,
+		"max_uses":  maxUses,
+		"temporary": temporary,
+	})
+	if err != nil {
+		return
+	}
+	resp, err := d.rest.Get("createinvite").
+		SetParams("channelid", channelid).
+		SetBody(bytes.NewReader(bmessage)).
+		Exec(&invite)
+	err = checkStatus(resp, err)
+	return
+}
+
+func (d *DiscordBot) GetInvite(code string) (invite Invite, err error) {
+	resp, err := d.rest.Get("getinvite").
+		SetParams("code", code).
+		Exec(&invite)
+	err = checkStatus(resp, err)
+	return
+}
+
+func (d *DiscordBot) GetGuildInvites(guildid string) (invites []Invite, err error) {
+	resp, err := d.rest.Get("getguildinvites").
+		SetParams("guildid", guildid).
+		Exec(&invites)
+	err = checkStatus(resp, err)
+	return
+}
+
+func (d *DiscordBot) DeleteInvite(code string) (err error) {
+	resp, err := d.rest.Get("deleteinvite").
+		SetParams("code", code).
+		Exec(nil)
+	return checkStatus(resp, err)
+}
+
+//AcceptInvite joins the guild of the invite
+func (d *DiscordBot) AcceptInvite(code string) (invite Invite, err error) {
+	resp, err := d.rest.Get("acceptinvite").
+		SetParams("code", code).
+		Exec(&invite)
+	err = checkStatus(resp, err)
 	return
 }
 
@@ -0,0 +1,176 @@
+package discordgo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func memberUpdate(t *testing.T, seq int, roles ...string) dGMUMessage {
+	var msg dGMUMessage
+	by, _ := json.Marshal(map[string]interface{}{
+		"t": EVENT_GUILD_MEMBER_UPDATE,
+		"s": seq,
+		"d": map[string]interface{}{
+			"guild_id": "1",
+			"user":     map[string]interface{}{"id": "2"},
+			"roles":    roles,
+		},
+	})
+	if err := json.Unmarshal(by, &msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestUpdateMemberIgnoresStaleUpdates(t *testing.T) {
+	d := NewDiscordBot()
+	d.setGuild(Guild{ID: "1", Members: []Member{{User: User{ID: "2"}}}})
+
+	d.updateMemberFromGuild(memberUpdate(t, 5, "a", "b"))
+	d.updateMemberFromGuild(memberUpdate(t, 4, "a"))
+
+	roles, exists := d.cachedMemberRoles("1", "2")
+	if !exists || len(roles) != 2 {
+		t.Errorf("stale update was applied, roles are %v", roles)
+	}
+}
+
+func TestMemberMutexIsRemoved(t *testing.T) {
+	d := NewDiscordBot()
+	first := d.lockMember("1:2")
+	done := make(chan bool)
+	go func() {
+		second := d.lockMember("1:2")
+		d.unlockMember("1:2", second)
+		done <- true
+	}()
+	d.unlockMember("1:2", first)
+	<-done
+	if len(d.memberMuts) != 0 {
+		t.Errorf("memberMuts still has %d entries", len(d.memberMuts))
+	}
+}
+
+func TestConcurrentCacheWrites(t *testing.T) {
+	d := NewDiscordBot()
+	d.setGuild(Guild{ID: "1", Channels: []Channel{{ID: "10"}}, Members: []Member{{User: User{ID: "2"}}}})
+	d.setGuild(Guild{ID: "4"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			d.updateRole("1", Role{ID: "3"})
+		}()
+		go func() {
+			defer wg.Done()
+			d.removeRoleFromGuild("1", "3")
+		}()
+		go func() {
+			defer wg.Done()
+			d.removeGuild("4")
+			d.setGuild(Guild{ID: "4"})
+		}()
+		go func() {
+			defer wg.Done()
+			d.GetGuildByChannelId("10")
+			d.resolveChannel(Message{ChannelID: "10"})
+		}()
+	}
+	wg.Wait()
+
+	if guild, _ := d.GetGuildById("1"); len(guild.Channels) != 1 || len(guild.Members) != 1 {
+		t.Errorf("guild 1 was overwritten: %+v", guild)
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *http.Response
+		err  error
+		fail bool
+	}{
+		{"no content", &http.Response{StatusCode: 204, Status: "204 No Content"}, nil, false},
+		{"forbidden", &http.Response{StatusCode: 403, Status: "403 Forbidden"}, nil, true},
+		{"transport error", nil, errors.New("connection refused"), true},
+	}
+	for _, test := range tests {
+		if err := checkStatus(test.resp, test.err); (err != nil) != test.fail {
+			t.Errorf("%s: got error %v", test.name, err)
+		}
+	}
+}
+
+func TestReadyStubKeepsAvailableGuild(t *testing.T) {
+	d := NewDiscordBot()
+	d.handleMessage(EVENT_GUILD_CREATE, []byte(`{"t":"GUILD_CREATE","d":{"id":"1","name":"guild","channels":[{"id":"10"}]}}`))
+	d.handleMessage(EVENT_READY, []byte(`{"t":"READY","d":{"heartbeat_interval":41250,"guilds":[{"id":"1","unavailable":true}]}}`))
+	d.stopHeartBeat()
+
+	guild, _ := d.GetGuildById("1")
+	if guild.Unavailable || guild.Name != "guild" || len(guild.Channels) != 1 {
+		t.Errorf("READY replaced the cached guild: %+v", guild)
+	}
+}
+
+func TestRoleEventsAfterLocalWrite(t *testing.T) {
+	d := NewDiscordBot()
+	d.setGuild(Guild{ID: "1"})
+	var changes []RoleChange
+	record := func(change RoleChange, d *DiscordBot) {
+		changes = append(changes, change)
+	}
+	d.AddRoleCallBack(EVENT_GUILD_ROLE_CREATE, record)
+	d.AddRoleCallBack(EVENT_GUILD_ROLE_DELETE, record)
+
+	role := Role{ID: "3", Name: "team"}
+	//what CreateRole does with the response
+	d.rememberPendingRole(d.updateRole("1", role), role.ID)
+	d.handleMessage(EVENT_GUILD_ROLE_CREATE, []byte(`{"t":"GUILD_ROLE_CREATE","d":{"guild_id":"1","role":{"id":"3","name":"team"}}}`))
+	//what DeleteRole does after the request
+	d.rememberPendingRole(d.removeRoleFromGuild("1", role.ID), role.ID)
+	d.handleMessage(EVENT_GUILD_ROLE_DELETE, []byte(`{"t":"GUILD_ROLE_DELETE","d":{"guild_id":"1","role_id":"3"}}`))
+
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(changes))
+	}
+	if changes[0].Old.ID != "" || changes[0].New != role {
+		t.Errorf("create event has Old %+v and New %+v", changes[0].Old, changes[0].New)
+	}
+	if changes[1].Old != role || changes[1].New.ID != "" {
+		t.Errorf("delete event has Old %+v and New %+v", changes[1].Old, changes[1].New)
+	}
+	if len(d.pendingRoles) != 0 {
+		t.Errorf("pending roles were not cleared: %v", d.pendingRoles)
+	}
+}
+
+func TestResolveChannel(t *testing.T) {
+	d := NewDiscordBot()
+	d.setGuild(Guild{ID: "1", Channels: []Channel{{ID: "10"}}})
+	d.addPrivateChannel(PrivateChannel{ID: "20"})
+
+	tests := []struct {
+		name    string
+		message Message
+		private bool
+		known   bool
+	}{
+		{"guild channel", Message{ChannelID: "10", GuildID: "1"}, false, true},
+		{"guild channel without guild id", Message{ChannelID: "10"}, false, true},
+		{"known private channel", Message{ChannelID: "20"}, true, true},
+		{"uncached channel with guild id", Message{ChannelID: "30", GuildID: "1"}, false, true},
+		{"uncached channel without guild id", Message{ChannelID: "30"}, false, false},
+	}
+	for _, test := range tests {
+		private, known := d.resolveChannel(test.message)
+		if private != test.private || known != test.known {
+			t.Errorf("%s: got private %v known %v, want %v %v", test.name, private, known, test.private, test.known)
+		}
+	}
+}
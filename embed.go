@@ -0,0 +1,201 @@
+package discordgo
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+//limits discord enforces on embeds, counted in characters
+const (
+	EMBED_TITLE_LIMIT       = 256
+	EMBED_DESCRIPTION_LIMIT = 2048
+	EMBED_FIELD_COUNT_LIMIT = 25
+	EMBED_FIELD_NAME_LIMIT  = 256
+	EMBED_FIELD_VALUE_LIMIT = 1024
+	EMBED_FOOTER_LIMIT      = 2048
+	EMBED_AUTHOR_LIMIT      = 256
+	EMBED_TOTAL_LIMIT       = 6000
+)
+
+//Embed of a message, either rich content sent by a bot or a link preview generated by discord
+type Embed struct {
+	Title       string          `json:"title,omitempty"`
+	Type        string          `json:"type,omitempty"`
+	Description string          `json:"description,omitempty"`
+	URL         string          `json:"url,omitempty"`
+	Timestamp   *time.Time      `json:"timestamp,omitempty"`
+	Color       int             `json:"color,omitempty"`
+	Footer      *EmbedFooter    `json:"footer,omitempty"`
+	Image       *EmbedImage     `json:"image,omitempty"`
+	Thumbnail   *EmbedThumbnail `json:"thumbnail,omitempty"`
+	Video       *EmbedVideo     `json:"video,omitempty"`
+	Provider    *EmbedProvider  `json:"provider,omitempty"`
+	Author      *EmbedAuthor    `json:"author,omitempty"`
+	Fields      []EmbedField    `json:"fields,omitempty"`
+}
+
+type EmbedFooter struct {
+	Text         string `json:"text,omitempty"`
+	IconURL      string `json:"icon_url,omitempty"`
+	ProxyIconURL string `json:"proxy_icon_url,omitempty"`
+}
+
+type EmbedImage struct {
+	URL      string `json:"url,omitempty"`
+	ProxyURL string `json:"proxy_url,omitempty"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+}
+
+type EmbedThumbnail struct {
+	URL      string `json:"url,omitempty"`
+	ProxyURL string `json:"proxy_url,omitempty"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+}
+
+type EmbedVideo struct {
+	URL    string `json:"url,omitempty"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+type EmbedProvider struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+type EmbedAuthor struct {
+	Name         string `json:"name,omitempty"`
+	URL          string `json:"url,omitempty"`
+	IconURL      string `json:"icon_url,omitempty"`
+	ProxyIconURL string `json:"proxy_icon_url,omitempty"`
+}
+
+type EmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+//Validate checks the embed against the limits discord enforces, so it fails before the request is sent
+func (e *Embed) Validate() error {
+	total := 0
+	check := func(what string, text string, limit int) error {
+		n := utf8.RuneCountInString(text)
+		total += n
+		if n > limit {
+			return fmt.Errorf("embed %s is %d characters long, the limit is %d", what, n, limit)
+		}
+		return nil
+	}
+
+	if err := check("title", e.Title, EMBED_TITLE_LIMIT); err != nil {
+		return err
+	}
+	if err := check("description", e.Description, EMBED_DESCRIPTION_LIMIT); err != nil {
+		return err
+	}
+	if len(e.Fields) > EMBED_FIELD_COUNT_LIMIT {
+		return fmt.Errorf("embed has %d fields, the limit is %d", len(e.Fields), EMBED_FIELD_COUNT_LIMIT)
+	}
+	for i, field := range e.Fields {
+		if field.Name == "" || field.Value == "" {
+			return fmt.Errorf("embed field %d needs a name and a value", i)
+		}
+		if err := check(fmt.Sprintf("field %d name", i), field.Name, EMBED_FIELD_NAME_LIMIT); err != nil {
+			return err
+		}
+		if err := check(fmt.Sprintf("field %d value", i), field.Value, EMBED_FIELD_VALUE_LIMIT); err != nil {
+			return err
+		}
+	}
+	if e.Footer != nil {
+		if err := check("footer text", e.Footer.Text, EMBED_FOOTER_LIMIT); err != nil {
+			return err
+		}
+	}
+	if e.Author != nil {
+		if err := check("author name", e.Author.Name, EMBED_AUTHOR_LIMIT); err != nil {
+			return err
+		}
+	}
+	if total > EMBED_TOTAL_LIMIT {
+		return fmt.Errorf("embed is %d characters long in total, the limit is %d", total, EMBED_TOTAL_LIMIT)
+	}
+	return nil
+}
+
+//EmbedBuilder builds an Embed for MessageRequest.Embed
+//
+//	embed, err := discordgo.NewEmbed().SetTitle("status").SetColor(0x00ff00).AddField("uptime", "3h", true).Build()
+type EmbedBuilder struct {
+	embed Embed
+}
+
+func NewEmbed() *EmbedBuilder {
+	return &EmbedBuilder{embed: Embed{Type: "rich"}}
+}
+
+func (b *EmbedBuilder) SetTitle(title string) *EmbedBuilder {
+	b.embed.Title = title
+	return b
+}
+
+func (b *EmbedBuilder) SetDescription(description string) *EmbedBuilder {
+	b.embed.Description = description
+	return b
+}
+
+func (b *EmbedBuilder) SetURL(url string) *EmbedBuilder {
+	b.embed.URL = url
+	return b
+}
+
+//SetColor sets the color of the left border as 0xRRGGBB
+func (b *EmbedBuilder) SetColor(color int) *EmbedBuilder {
+	b.embed.Color = color
+	return b
+}
+
+func (b *EmbedBuilder) AddField(name string, value string, inline bool) *EmbedBuilder {
+	b.embed.Fields = append(b.embed.Fields, EmbedField{Name: name, Value: value, Inline: inline})
+	return b
+}
+
+func (b *EmbedBuilder) SetFooter(text string, iconURL string) *EmbedBuilder {
+	b.embed.Footer = &EmbedFooter{Text: text, IconURL: iconURL}
+	return b
+}
+
+func (b *EmbedBuilder) SetAuthor(name string, url string, iconURL string) *EmbedBuilder {
+	b.embed.Author = &EmbedAuthor{Name: name, URL: url, IconURL: iconURL}
+	return b
+}
+
+func (b *EmbedBuilder) SetThumbnail(url string) *EmbedBuilder {
+	b.embed.Thumbnail = &EmbedThumbnail{URL: url}
+	return b
+}
+
+func (b *EmbedBuilder) SetImage(url string) *EmbedBuilder {
+	b.embed.Image = &EmbedImage{URL: url}
+	return b
+}
+
+func (b *EmbedBuilder) SetTimestamp(t time.Time) *EmbedBuilder {
+	b.embed.Timestamp = &t
+	return b
+}
+
+//Build validates the embed and returns a copy of it
+func (b *EmbedBuilder) Build() (*Embed, error) {
+	embed := b.embed
+	embed.Fields = append([]EmbedField(nil), b.embed.Fields...)
+	err := embed.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return &embed, nil
+}
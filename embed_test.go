@@ -0,0 +1,67 @@
+package discordgo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEmbedValidate(t *testing.T) {
+	fields := func(n int) []EmbedField {
+		var f []EmbedField
+		for i := 0; i < n; i++ {
+			f = append(f, EmbedField{Name: "name", Value: "value"})
+		}
+		return f
+	}
+
+	tests := []struct {
+		name  string
+		embed Embed
+		fail  bool
+	}{
+		{"empty", Embed{}, false},
+		{"title at the limit", Embed{Title: strings.Repeat("t", EMBED_TITLE_LIMIT)}, false},
+		{"title over the limit", Embed{Title: strings.Repeat("t", EMBED_TITLE_LIMIT+1)}, true},
+		{"multibyte title at the limit", Embed{Title: strings.Repeat("ä", EMBED_TITLE_LIMIT)}, false},
+		{"description over the limit", Embed{Description: strings.Repeat("d", EMBED_DESCRIPTION_LIMIT+1)}, true},
+		{"25 fields", Embed{Fields: fields(EMBED_FIELD_COUNT_LIMIT)}, false},
+		{"26 fields", Embed{Fields: fields(EMBED_FIELD_COUNT_LIMIT + 1)}, true},
+		{"field without value", Embed{Fields: []EmbedField{{Name: "name"}}}, true},
+		{"field name over the limit", Embed{Fields: []EmbedField{{Name: strings.Repeat("n", EMBED_FIELD_NAME_LIMIT+1), Value: "v"}}}, true},
+		{"field value over the limit", Embed{Fields: []EmbedField{{Name: "n", Value: strings.Repeat("v", EMBED_FIELD_VALUE_LIMIT+1)}}}, true},
+		{"footer over the limit", Embed{Footer: &EmbedFooter{Text: strings.Repeat("f", EMBED_FOOTER_LIMIT+1)}}, true},
+		{"author over the limit", Embed{Author: &EmbedAuthor{Name: strings.Repeat("a", EMBED_AUTHOR_LIMIT+1)}}, true},
+		{"total at the limit", Embed{
+			Description: strings.Repeat("d", EMBED_DESCRIPTION_LIMIT),
+			Footer:      &EmbedFooter{Text: strings.Repeat("f", EMBED_FOOTER_LIMIT)},
+			Fields: []EmbedField{{Name: "n", Value: strings.Repeat("v", EMBED_FIELD_VALUE_LIMIT)},
+				{Name: "n", Value: strings.Repeat("v", EMBED_TOTAL_LIMIT-EMBED_DESCRIPTION_LIMIT-EMBED_FOOTER_LIMIT-EMBED_FIELD_VALUE_LIMIT-2)}},
+		}, false},
+		{"total over the limit", Embed{
+			Description: strings.Repeat("d", EMBED_DESCRIPTION_LIMIT),
+			Footer:      &EmbedFooter{Text: strings.Repeat("f", EMBED_FOOTER_LIMIT)},
+			Fields: []EmbedField{{Name: "n", Value: strings.Repeat("v", EMBED_FIELD_VALUE_LIMIT)},
+				{Name: "n", Value: strings.Repeat("v", EMBED_TOTAL_LIMIT-EMBED_DESCRIPTION_LIMIT-EMBED_FOOTER_LIMIT-EMBED_FIELD_VALUE_LIMIT-1)}},
+		}, true},
+	}
+	for _, test := range tests {
+		if err := test.embed.Validate(); (err != nil) != test.fail {
+			t.Errorf("%s: got error %v", test.name, err)
+		}
+	}
+}
+
+func TestEmbedBuilderCopiesFields(t *testing.T) {
+	builder := NewEmbed().SetTitle("status").AddField("uptime", "3h", true)
+	embed, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	builder.AddField("load", "0.5", true)
+	if len(embed.Fields) != 1 {
+		t.Errorf("built embed has %d fields after the builder changed", len(embed.Fields))
+	}
+	if _, err := NewEmbed().AddField("", "value", false).Build(); err == nil {
+		t.Errorf("embed with an empty field name was built")
+	}
+}
@@ -0,0 +1,24 @@
+package discordgo
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+//EncodeImage reads the image and returns it as data uri like discord expects it for avatars and icons,
+//the image type is detected from the content
+func EncodeImage(img io.Reader) (string, error) {
+	by, err := ioutil.ReadAll(img)
+	if err != nil {
+		return "", err
+	}
+	contentType := http.DetectContentType(by)
+	if !strings.HasPrefix(contentType, "image/") {
+		return "", errors.New("unsupported image type " + contentType)
+	}
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(by), nil
+}
@@ -0,0 +1,158 @@
+//Package markdown escapes user supplied text and builds the markdown discord renders in messages
+package markdown
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+const zeroWidthSpace = "\u200b"
+
+var escaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"`", "\\`",
+	"|", `\|`,
+	">", `\>`,
+)
+
+//Raw is markdown that is sent as it is, wrap the output of the builders in it to pass it to
+//discordgo.NewMarkdownMessage next to user supplied text that is escaped
+type Raw string
+
+//Escape escapes every markdown character in text, so it is shown as it is
+func Escape(text string) string {
+	return escaper.Replace(text)
+}
+
+//EscapeBold escapes * so text can't start or end bold text
+func EscapeBold(text string) string {
+	return strings.Replace(text, "*", `\*`, -1)
+}
+
+//EscapeItalics escapes * and _ so text can't start or end italic text
+func EscapeItalics(text string) string {
+	return strings.NewReplacer("*", `\*`, "_", `\_`).Replace(text)
+}
+
+//EscapeUnderline escapes _ so text can't start or end underlined text
+func EscapeUnderline(text string) string {
+	return strings.Replace(text, "_", `\_`, -1)
+}
+
+//EscapeStrikethrough escapes ~ so text can't start or end strikethrough text
+func EscapeStrikethrough(text string) string {
+	return strings.Replace(text, "~", `\~`, -1)
+}
+
+//EscapeSpoiler escapes | so text can't start or end a spoiler
+func EscapeSpoiler(text string) string {
+	return strings.Replace(text, "|", `\|`, -1)
+}
+
+//EscapeCode escapes ` so text can't start or end a code span
+func EscapeCode(text string) string {
+	return strings.Replace(text, "`", "\\`", -1)
+}
+
+//EscapeCodeBlock breaks up ``` in text so it can't end a code block, backslashes are not
+//interpreted inside of code blocks so a zero width space is used
+func EscapeCodeBlock(text string) string {
+	return strings.Replace(text, "```", "`"+zeroWidthSpace+"`"+zeroWidthSpace+"`", -1)
+}
+
+//EscapeQuote escapes > at the start of every line so text can't start a quote
+func EscapeQuote(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ">") {
+			lines[i] = `\` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func Bold(text string) string {
+	return "**" + text + "**"
+}
+
+func Italics(text string) string {
+	return "*" + text + "*"
+}
+
+func Underline(text string) string {
+	return "__" + text + "__"
+}
+
+func Strikethrough(text string) string {
+	return "~~" + text + "~~"
+}
+
+func Spoiler(text string) string {
+	return "||" + text + "||"
+}
+
+//Code returns text as code span, the content of code spans is not interpreted as markdown.
+//Text with backticks is wrapped in double backticks, a zero width space between adjacent backticks
+//makes sure no run of two or more of them is left to end the span early
+func Code(text string) string {
+	if !strings.Contains(text, "`") {
+		return "`" + text + "`"
+	}
+	var b bytes.Buffer
+	for i, r := range text {
+		if r == '`' && i > 0 && text[i-1] == '`' {
+			b.WriteString(zeroWidthSpace)
+		}
+		b.WriteRune(r)
+	}
+	return "`` " + b.String() + " ``"
+}
+
+//CodeBlock returns text as code block highlighted as language, language may be empty
+func CodeBlock(language string, text string) string {
+	return "```" + language + "\n" + EscapeCodeBlock(text) + "\n```"
+}
+
+//Quote prefixes every line of text with >
+func Quote(text string) string {
+	return "> " + strings.Replace(text, "\n", "\n> ", -1)
+}
+
+//Table renders the rows as table with aligned columns inside of a code block
+func Table(header []string, rows [][]string) string {
+	widths := make([]int, len(header))
+	all := append([][]string{header}, rows...)
+	for _, row := range all {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	line := func(row []string) string {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		}
+		return strings.TrimRight(strings.Join(cells, " | "), " ")
+	}
+
+	separator := make([]string, len(widths))
+	for i, width := range widths {
+		separator[i] = strings.Repeat("-", width)
+	}
+
+	lines := []string{line(header), strings.Join(separator, "-+-")}
+	for _, row := range rows {
+		lines = append(lines, line(row))
+	}
+	return CodeBlock("", strings.Join(lines, "\n"))
+}
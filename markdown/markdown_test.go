@@ -0,0 +1,96 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+const z = zeroWidthSpace
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"fmt.Println()", "`fmt.Println()`"},
+		{"a`b", "`` a`b ``"},
+		{"a``b", "`` a`" + z + "`b ``"},
+		{"a```b", "`` a`" + z + "`" + z + "`b ``"},
+		{"`", "`` ` ``"},
+		{"``", "`` `" + z + "` ``"},
+	}
+	for _, test := range tests {
+		got := Code(test.text)
+		if got != test.want {
+			t.Errorf("Code(%q) = %q, want %q", test.text, got, test.want)
+		}
+		inner := strings.TrimSuffix(strings.TrimPrefix(got, "`` "), " ``")
+		if strings.Contains(inner, "``") {
+			t.Errorf("Code(%q) = %q keeps a run of backticks", test.text, got)
+		}
+	}
+}
+
+func TestCodeBlock(t *testing.T) {
+	tests := []struct {
+		language string
+		text     string
+		want     string
+	}{
+		{"go", "x := 1", "```go\nx := 1\n```"},
+		{"", "a\nb", "```\na\nb\n```"},
+		{"", "```evil```", "```\n`" + z + "`" + z + "`evil`" + z + "`" + z + "`\n```"},
+	}
+	for _, test := range tests {
+		if got := CodeBlock(test.language, test.text); got != test.want {
+			t.Errorf("CodeBlock(%q, %q) = %q, want %q", test.language, test.text, got, test.want)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name   string
+		escape func(string) string
+		text   string
+		want   string
+	}{
+		{"Escape", Escape, `*b* _i_ ~~s~~ ||sp|| ` + "`c`" + ` > q \`, `\*b\* \_i\_ \~\~s\~\~ \|\|sp\|\| \` + "`c\\`" + ` \> q \\`},
+		{"Escape plain", Escape, "nothing here", "nothing here"},
+		{"EscapeBold", EscapeBold, "**b** _i_", `\*\*b\*\* _i_`},
+		{"EscapeItalics", EscapeItalics, "*i* _i_ ~s~", `\*i\* \_i\_ ~s~`},
+		{"EscapeUnderline", EscapeUnderline, "__u__ *b*", `\_\_u\_\_ *b*`},
+		{"EscapeStrikethrough", EscapeStrikethrough, "~~s~~", `\~\~s\~\~`},
+		{"EscapeSpoiler", EscapeSpoiler, "||sp||", `\|\|sp\|\|`},
+		{"EscapeCode", EscapeCode, "`c`", "\\`c\\`"},
+		{"EscapeCodeBlock", EscapeCodeBlock, "a```b", "a`" + z + "`" + z + "`b"},
+		{"EscapeQuote", EscapeQuote, "> q\nnot > q\n>> qq", "\\> q\nnot > q\n\\>> qq"},
+	}
+	for _, test := range tests {
+		if got := test.escape(test.text); got != test.want {
+			t.Errorf("%s(%q) = %q, want %q", test.name, test.text, got, test.want)
+		}
+	}
+}
+
+func TestTable(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []string
+		rows   [][]string
+		want   string
+	}{
+		{"aligned", []string{"name", "id"}, [][]string{{"a", "1234"}, {"bcdef", "5"}},
+			"```\nname  | id\n------+-----\na     | 1234\nbcdef | 5\n```"},
+		{"multibyte", []string{"ä", "b"}, [][]string{{"äöü", "x"}},
+			"```\nä   | b\n----+--\näöü | x\n```"},
+		{"header only", []string{"a", "b"}, nil, "```\na | b\n--+--\n```"},
+		{"row longer than header", []string{"a"}, [][]string{{"x", "yy"}},
+			"```\na\n--+---\nx | yy\n```"},
+	}
+	for _, test := range tests {
+		if got := Table(test.header, test.rows); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package discordgo
+
+import (
+	"fmt"
+	"regexp"
+)
+
+type MentionType int
+
+const (
+	MENTION_USER MentionType = iota
+	MENTION_CHANNEL
+	MENTION_ROLE
+	MENTION_EMOJI
+)
+
+//Mention is a reference found in the content of a message, Name is only set for custom emojis
+type Mention struct {
+	Type     MentionType
+	ID       string
+	Name     string
+	Animated bool
+	//the token as it appears in the content, e.g. <@!1234>
+	Raw string
+}
+
+var mentionRegex = regexp.MustCompile(`<(@!?|@&|#)(\d+)>|<(a?):(\w+):(\d+)>`)
+
+//ParseMentions returns the user (<@id>, <@!id>), channel (<#id>), role (<@&id>)
+//and custom emoji (<:name:id>, <a:name:id>) references in the order they appear in content
+func ParseMentions(content string) (mentions []Mention) {
+	for _, match := range mentionRegex.FindAllStringSubmatch(content, -1) {
+		mentions = append(mentions, toMention(match))
+	}
+	return
+}
+
+func toMention(match []string) Mention {
+	switch match[1] {
+	case "@", "@!":
+		return Mention{Type: MENTION_USER, ID: match[2], Raw: match[0]}
+	case "@&":
+		return Mention{Type: MENTION_ROLE, ID: match[2], Raw: match[0]}
+	case "#":
+		return Mention{Type: MENTION_CHANNEL, ID: match[2], Raw: match[0]}
+	}
+	return Mention{Type: MENTION_EMOJI, ID: match[5], Name: match[4], Animated: match[3] == "a", Raw: match[0]}
+}
+
+//CleanContent returns the content of the message with user, channel and role mentions replaced by their names
+//and custom emojis replaced by :name:, mentions that can't be resolved from the cache are kept
+func (d *DiscordBot) CleanContent(message Message) string {
+	d.stateMut.RLock()
+	defer d.stateMut.RUnlock()
+	guild, _ := d.guildByChannelId(message.ChannelID)
+	return mentionRegex.ReplaceAllStringFunc(message.Content, func(raw string) string {
+		mention := toMention(mentionRegex.FindStringSubmatch(raw))
+		switch mention.Type {
+		case MENTION_USER:
+			for _, user := range message.Mentions {
+				if user.ID == mention.ID {
+					return "@" + user.Username
+				}
+			}
+			member, _ := d.memberById(mention.ID, guild)
+			if member.User.ID != "" {
+				return "@" + member.User.Username
+			}
+		case MENTION_ROLE:
+			for _, role := range guild.Roles {
+				if role.ID == mention.ID {
+					return "@" + role.Name
+				}
+			}
+		case MENTION_CHANNEL:
+			cguild, _ := d.guildByChannelId(mention.ID)
+			channel, _ := d.channelById(mention.ID, cguild)
+			if channel.ID != "" {
+				return "#" + channel.Name
+			}
+		case MENTION_EMOJI:
+			return ":" + mention.Name + ":"
+		}
+		return raw
+	})
+}
+
+//GetGuildByChannelId returns the guild the channel belongs to, empty for private channels
+func (d *DiscordBot) GetGuildByChannelId(channelid string) (guild Guild, index int) {
+	d.stateMut.RLock()
+	defer d.stateMut.RUnlock()
+	return d.guildByChannelId(channelid)
+}
+
+func (d *DiscordBot) guildByChannelId(channelid string) (guild Guild, index int) {
+	for idx, guild1 := range d.Guilds {
+		channel, _ := d.channelById(channelid, guild1)
+		if channel.ID != "" {
+			return guild1, idx
+		}
+	}
+	return
+}
+
+func (c Channel) Mention() string {
+	return fmt.Sprintf("<#%v>", c.ID)
+}
+
+func (r Role) Mention() string {
+	return fmt.Sprintf("<@&%v>", r.ID)
+}
+
+//EmojiMention formats a custom emoji so it is rendered in a message
+func EmojiMention(name string, id string, animated bool) string {
+	if animated {
+		return fmt.Sprintf("<a:%v:%v>", name, id)
+	}
+	return fmt.Sprintf("<:%v:%v>", name, id)
+}
@@ -0,0 +1,58 @@
+package discordgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []Mention
+	}{
+		{"no mentions", nil},
+		{"hi <@12>", []Mention{{Type: MENTION_USER, ID: "12", Raw: "<@12>"}}},
+		{"hi <@!12>", []Mention{{Type: MENTION_USER, ID: "12", Raw: "<@!12>"}}},
+		{"<@&5> in <#10>", []Mention{
+			{Type: MENTION_ROLE, ID: "5", Raw: "<@&5>"},
+			{Type: MENTION_CHANNEL, ID: "10", Raw: "<#10>"},
+		}},
+		{"<:blob:77><a:dance:78>", []Mention{
+			{Type: MENTION_EMOJI, ID: "77", Name: "blob", Raw: "<:blob:77>"},
+			{Type: MENTION_EMOJI, ID: "78", Name: "dance", Animated: true, Raw: "<a:dance:78>"},
+		}},
+		{"<@abc> <#> <:blob:> @everyone", nil},
+	}
+	for _, test := range tests {
+		if got := ParseMentions(test.content); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %+v, want %+v", test.content, got, test.want)
+		}
+	}
+}
+
+func TestCleanContent(t *testing.T) {
+	d := NewDiscordBot()
+	d.setGuild(Guild{
+		ID:       "1",
+		Channels: []Channel{{ID: "10", Name: "general"}},
+		Roles:    []Role{{ID: "5", Name: "mods"}},
+		Members:  []Member{{User: User{ID: "13", Username: "bob"}}},
+	})
+
+	tests := []struct {
+		message Message
+		want    string
+	}{
+		{Message{ChannelID: "10", Content: "hi <@12>", Mentions: []User{{ID: "12", Username: "alice"}}}, "hi @alice"},
+		{Message{ChannelID: "10", Content: "hi <@!13>"}, "hi @bob"},
+		{Message{ChannelID: "10", Content: "<@&5> see <#10>"}, "@mods see #general"},
+		{Message{ChannelID: "10", Content: "<a:dance:78>"}, ":dance:"},
+		{Message{ChannelID: "10", Content: "<@99> <@&98> <#97>"}, "<@99> <@&98> <#97>"},
+		{Message{ChannelID: "20", Content: "<@&5>"}, "<@&5>"},
+	}
+	for _, test := range tests {
+		if got := d.CleanContent(test.message); got != test.want {
+			t.Errorf("%q: got %q, want %q", test.message.Content, got, test.want)
+		}
+	}
+}
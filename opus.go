@@ -0,0 +1,70 @@
+package discordgo
+
+import (
+	"io"
+	"time"
+)
+
+//time between two opus frames, OPUS_FRAME_SIZE samples at 48kHz
+const opusFrameInterval = 20 * time.Millisecond
+
+//number of silence frames sent after the last frame
+const opusSilenceFrames = 5
+
+//VoiceEncrypter encrypts the opus frame of an outgoing voice packet. Discord expects xsalsa20_poly1305 with the
+//session key and the rtp header padded to 24 bytes as nonce, the voice connection has to provide it
+type VoiceEncrypter interface {
+	Seal(header RTPHeader, frame []byte) []byte
+}
+
+//OpusSender sends the opus frames written to OpusSend as rtp packets, paced at one frame every 20ms
+type OpusSender struct {
+	OpusSend  chan []byte
+	conn      io.Writer
+	encrypter VoiceEncrypter
+	sequencer *RTPSequencer
+	interval  time.Duration
+}
+
+//NewOpusSender creates a sender writing to conn, usually the udp socket of the voice connection
+func NewOpusSender(conn io.Writer, ssrc uint32, encrypter VoiceEncrypter) *OpusSender {
+	return &OpusSender{
+		OpusSend:  make(chan []byte, 2),
+		conn:      conn,
+		encrypter: encrypter,
+		sequencer: NewRTPSequencer(ssrc),
+		interval:  opusFrameInterval,
+	}
+}
+
+//Run sends the frames until OpusSend is closed and then sends five frames of silence.
+//It returns the first write error, frames written to OpusSend after that are not read anymore
+func (s *OpusSender) Run() error {
+	var next time.Time
+	send := func(frame []byte) error {
+		now := time.Now()
+		if now.Sub(next) > s.interval {
+			//first frame or the frames stopped coming for a while, start the clock again
+			next = now
+		} else if next.After(now) {
+			time.Sleep(next.Sub(now))
+		}
+		//frames are scheduled from the last deadline, so a late wake up doesn't add up over time
+		next = next.Add(s.interval)
+		header := s.sequencer.Next()
+		_, err := s.conn.Write(append(header.Bytes(), s.encrypter.Seal(header, frame)...))
+		return err
+	}
+
+	for frame := range s.OpusSend {
+		if err := send(frame); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < opusSilenceFrames; i++ {
+		if err := send(opusSilence); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package discordgo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+//prefixes the frame with the low byte of the sequence so the test can check which header was used
+type testEncrypter struct{}
+
+func (testEncrypter) Seal(header RTPHeader, frame []byte) []byte {
+	return append([]byte{byte(header.Sequence)}, frame...)
+}
+
+type packetWriter struct {
+	packets [][]byte
+	times   []time.Time
+	fail    bool
+}
+
+func (w *packetWriter) Write(p []byte) (int, error) {
+	if w.fail {
+		return 0, errors.New("connection closed")
+	}
+	w.packets = append(w.packets, append([]byte(nil), p...))
+	w.times = append(w.times, time.Now())
+	return len(p), nil
+}
+
+func TestOpusSender(t *testing.T) {
+	w := &packetWriter{}
+	s := NewOpusSender(w, 0x01020304, testEncrypter{})
+	s.interval = 5 * time.Millisecond
+	frames := [][]byte{{1}, {2, 2}, {3, 3, 3}}
+	go func() {
+		for _, frame := range frames {
+			s.OpusSend <- frame
+		}
+		close(s.OpusSend)
+	}()
+	if err := s.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(w.packets) != len(frames)+opusSilenceFrames {
+		t.Fatalf("got %d packets, want %d", len(w.packets), len(frames)+opusSilenceFrames)
+	}
+	for i, packet := range w.packets {
+		sequence := binary.BigEndian.Uint16(packet[2:4])
+		if ssrc := binary.BigEndian.Uint32(packet[8:12]); ssrc != 0x01020304 {
+			t.Errorf("packet %d has ssrc %x", i, ssrc)
+		}
+		if packet[RTP_HEADER_SIZE] != byte(sequence) {
+			t.Errorf("packet %d was not sealed with its own header", i)
+		}
+		if i > 0 {
+			previous := w.packets[i-1]
+			if sequence != binary.BigEndian.Uint16(previous[2:4])+1 {
+				t.Errorf("packet %d has sequence %d after %d", i, sequence, binary.BigEndian.Uint16(previous[2:4]))
+			}
+			if ts := binary.BigEndian.Uint32(packet[4:8]); ts != binary.BigEndian.Uint32(previous[4:8])+OPUS_FRAME_SIZE {
+				t.Errorf("packet %d has timestamp %d", i, ts)
+			}
+		}
+		want := opusSilence
+		if i < len(frames) {
+			want = frames[i]
+		}
+		if !bytes.Equal(packet[RTP_HEADER_SIZE+1:], want) {
+			t.Errorf("packet %d has frame %v, want %v", i, packet[RTP_HEADER_SIZE+1:], want)
+		}
+	}
+	if elapsed := w.times[len(w.times)-1].Sub(w.times[0]); elapsed < time.Duration(len(w.packets)-1)*s.interval {
+		t.Errorf("%d packets were sent within %v", len(w.packets), elapsed)
+	}
+}
+
+func TestOpusSenderWriteError(t *testing.T) {
+	s := NewOpusSender(&packetWriter{fail: true}, 1, testEncrypter{})
+	s.OpusSend <- []byte{1}
+	if err := s.Run(); err == nil {
+		t.Errorf("expected the write error")
+	}
+}
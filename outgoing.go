@@ -0,0 +1,179 @@
+package discordgo
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+//maximum length of the content of a message in characters
+const MESSAGE_LENGTH_LIMIT = 2000
+
+//MentionPolicy decides what SendMessage does with @everyone, @here and role mentions in the content
+type MentionPolicy int
+
+const (
+	//mentions are sent as they are
+	MENTIONS_ALLOW MentionPolicy = iota
+	//mentions stay readable but don't notify anyone
+	MENTIONS_ESCAPE
+	//mentions are removed from the content
+	MENTIONS_REMOVE
+)
+
+const zeroWidthSpace = "\u200b"
+
+var (
+	//the mention has to stand on its own, so mail addresses like me@here.com are kept
+	everyoneRegex = regexp.MustCompile(`(^|[^\w])@(everyone|here)\b`)
+	roleRegex     = regexp.MustCompile(`<@&(\d+)>`)
+)
+
+//SetEveryonePolicy sets how @everyone and @here in the content of sent messages are treated
+func (d *DiscordBot) SetEveryonePolicy(policy MentionPolicy) {
+	d.everyonePolicy = policy
+}
+
+//SetRolePolicy sets how role mentions in the content of sent messages are treated
+func (d *DiscordBot) SetRolePolicy(policy MentionPolicy) {
+	d.rolePolicy = policy
+}
+
+//SanitizeContent applies the everyone and role policies to the content,
+//escaped role mentions are replaced by the role name
+func (d *DiscordBot) SanitizeContent(content string) string {
+	switch d.everyonePolicy {
+	case MENTIONS_ESCAPE:
+		content = everyoneRegex.ReplaceAllString(content, "${1}@"+zeroWidthSpace+"${2}")
+	case MENTIONS_REMOVE:
+		content = everyoneRegex.ReplaceAllString(content, "${1}")
+	}
+	switch d.rolePolicy {
+	case MENTIONS_ESCAPE:
+		content = roleRegex.ReplaceAllStringFunc(content, func(raw string) string {
+			roleid := roleRegex.FindStringSubmatch(raw)[1]
+			d.stateMut.RLock()
+			defer d.stateMut.RUnlock()
+			for _, guild := range d.Guilds {
+				for _, role := range guild.Roles {
+					if role.ID == roleid {
+						return "@" + zeroWidthSpace + role.Name
+					}
+				}
+			}
+			return "@" + zeroWidthSpace + roleid
+		})
+	case MENTIONS_REMOVE:
+		content = roleRegex.ReplaceAllString(content, "")
+	}
+	return content
+}
+
+//SplitMessage splits content into parts of at most limit characters. It splits at line breaks where possible
+//and closes an open code block at the end of a part and opens it again with the same language in the next one
+func SplitMessage(content string, limit int) (parts []string) {
+	if utf8.RuneCountInString(content) <= limit {
+		return []string{content}
+	}
+
+	var lines []string
+	length := 0
+	//opener of the code block we are in, empty outside of code blocks
+	fence := ""
+	//the part only holds the fence that was reopened after a flush
+	reopened := false
+	flush := func() {
+		part := strings.Join(lines, "\n")
+		if fence != "" {
+			part += "\n```"
+		}
+		parts = append(parts, part)
+		lines, length, reopened = nil, 0, false
+		if fence != "" {
+			lines, length, reopened = []string{fence}, utf8.RuneCountInString(fence), true
+		}
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		nextFence := fence
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "```") && strings.Count(trimmed, "```") == 1 {
+			if fence == "" {
+				nextFence = fenceOpener(trimmed)
+				//a part has to hold the reopened fence, a line and the closing fence,
+				//an opener that leaves no room for that is kept as plain text
+				if utf8.RuneCountInString(nextFence)+len("\n\n```")+1 > limit {
+					nextFence = ""
+				}
+			} else {
+				nextFence = ""
+			}
+		}
+
+		for {
+			//room for closing the code block if the part ends after this line
+			reserve := 0
+			if nextFence != "" {
+				reserve = len("\n```")
+			}
+			sep := 0
+			if len(lines) > 0 {
+				sep = 1
+			}
+			n := utf8.RuneCountInString(line)
+			if length+sep+n+reserve <= limit {
+				lines = append(lines, line)
+				length += sep + n
+				reopened = false
+				break
+			}
+			//flushing a part that holds nothing but the opener of the code block would not make room
+			onlyFence := fence != "" && len(lines) == 1 && strings.TrimSpace(lines[0]) == fence
+			if len(lines) > 0 && !onlyFence {
+				flush()
+				continue
+			}
+
+			//the line doesn't even fit into an empty part, flush closes the code block the line is in
+			closing := 0
+			if fence != "" {
+				closing = len("\n```")
+			}
+			head, tail := splitLine(line, limit-length-sep-closing)
+			lines = append(lines, head)
+			flush()
+			line = tail
+		}
+		fence = nextFence
+	}
+
+	if len(lines) > 0 && !reopened {
+		parts = append(parts, strings.Join(lines, "\n"))
+	}
+	return
+}
+
+//fenceOpener returns the backticks and the language of the line opening a code block, code on the same line is dropped
+func fenceOpener(line string) string {
+	if fields := strings.Fields(strings.TrimPrefix(line, "```")); len(fields) > 0 {
+		return "```" + fields[0]
+	}
+	return "```"
+}
+
+//splitLine splits the line after at most n characters, at the last space if there is one in the second half,
+//the space itself is dropped
+func splitLine(line string, n int) (string, string) {
+	if n < 1 {
+		n = 1
+	}
+	runes := []rune(line)
+	if len(runes) <= n {
+		return line, ""
+	}
+	for i := n; i > n/2; i-- {
+		if runes[i] == ' ' {
+			return string(runes[:i]), string(runes[i+1:])
+		}
+	}
+	return string(runes[:n]), string(runes[n:])
+}
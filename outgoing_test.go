@@ -0,0 +1,110 @@
+package discordgo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		limit   int
+		want    []string
+	}{
+		{"fits", "hello", 10, []string{"hello"}},
+		{"at line breaks", "aaaa\nbbbb\ncccc", 10, []string{"aaaa\nbbbb", "cccc"}},
+		{"code block carried over", "```go\naaaa\nbbbb\ncccc\n```\nafter", 16,
+			[]string{"```go\naaaa\n```", "```go\nbbbb\n```", "```go\ncccc\n```", "after"}},
+		{"closing fence at the end of a part", "```go\naaaa\nbbbb\n```\nafter", 19,
+			[]string{"```go\naaaa\nbbbb\n```", "after"}},
+		{"line longer than the limit", strings.Repeat("x", 25), 10,
+			[]string{"xxxxxxxxxx", "xxxxxxxxxx", "xxxxx"}},
+		{"long line split at a space", "word word word word", 12, []string{"word word", "word word"}},
+		{"long line in a code block", "```\n" + strings.Repeat("x", 8), 10,
+			[]string{"```\nxx\n```", "```\nxx\n```", "```\nxx\n```", "```\nxx"}},
+		{"multibyte", "äöü äöü äöü äöü", 8, []string{"äöü äöü", "äöü äöü"}},
+		{"long opener reopened as backticks and language", "```go " + strings.Repeat("y", 20) + "\naaaa\nbbbb", 16,
+			[]string{"```go yyyyyyyyyy", "yyyyyyyyyy\n```", "```go\naaaa\n```", "```go\nbbbb"}},
+		{"unclosed fence as the last line", "aaaa\n```go", 20, []string{"aaaa\n```go"}},
+		{"emoji", "😀😀😀😀😀😀", 4, []string{"😀😀😀😀", "😀😀"}},
+	}
+	for _, test := range tests {
+		parts := SplitMessage(test.content, test.limit)
+		if !reflect.DeepEqual(parts, test.want) {
+			t.Errorf("%s: got %q, want %q", test.name, parts, test.want)
+		}
+		for _, part := range parts {
+			if n := utf8.RuneCountInString(part); n > test.limit {
+				t.Errorf("%s: part %q has %d characters", test.name, part, n)
+			}
+		}
+	}
+}
+
+func TestSplitMessageLongFence(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		lines   int
+	}{
+		{"fence longer than the limit", "```" + strings.Repeat("x", 2500) + "\n" + strings.Repeat("line\n", 600) + "```", 600},
+		{"fence without newline", "```" + strings.Repeat("x", 2500), 0},
+	}
+	for _, test := range tests {
+		done := make(chan []string)
+		go func() {
+			done <- SplitMessage(test.content, MESSAGE_LENGTH_LIMIT)
+		}()
+		var parts []string
+		select {
+		case parts = <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: SplitMessage did not return", test.name)
+		}
+		joined := strings.Join(parts, "\n")
+		if n := strings.Count(joined, "x"); n != 2500 {
+			t.Errorf("%s: %d of 2500 characters were kept", test.name, n)
+		}
+		if n := strings.Count(joined, "line"); n != test.lines {
+			t.Errorf("%s: %d of %d lines were kept", test.name, n, test.lines)
+		}
+		for _, part := range parts {
+			if n := utf8.RuneCountInString(part); n > MESSAGE_LENGTH_LIMIT {
+				t.Errorf("%s: part has %d characters", test.name, n)
+			}
+		}
+	}
+}
+
+func TestSanitizeContent(t *testing.T) {
+	d := NewDiscordBot()
+	d.setGuild(Guild{ID: "1", Roles: []Role{{ID: "5", Name: "mods"}}})
+
+	tests := []struct {
+		everyone MentionPolicy
+		role     MentionPolicy
+		content  string
+		want     string
+	}{
+		{MENTIONS_ALLOW, MENTIONS_ALLOW, "@everyone <@&5>", "@everyone <@&5>"},
+		{MENTIONS_ESCAPE, MENTIONS_ALLOW, "@everyone and @here", "@\u200beveryone and @\u200bhere"},
+		{MENTIONS_ESCAPE, MENTIONS_ALLOW, "(@here)", "(@\u200bhere)"},
+		{MENTIONS_REMOVE, MENTIONS_ALLOW, "hi @everyone!", "hi !"},
+		{MENTIONS_ESCAPE, MENTIONS_ALLOW, "me@here.com", "me@here.com"},
+		{MENTIONS_REMOVE, MENTIONS_ALLOW, "mail me@everyone.org", "mail me@everyone.org"},
+		{MENTIONS_ESCAPE, MENTIONS_ALLOW, "@heretic @everyones", "@heretic @everyones"},
+		{MENTIONS_ALLOW, MENTIONS_ESCAPE, "<@&5> and <@&6>", "@\u200bmods and @\u200b6"},
+		{MENTIONS_ALLOW, MENTIONS_REMOVE, "hi <@&5>", "hi "},
+	}
+	for _, test := range tests {
+		d.SetEveryonePolicy(test.everyone)
+		d.SetRolePolicy(test.role)
+		if got := d.SanitizeContent(test.content); got != test.want {
+			t.Errorf("%q: got %q, want %q", test.content, got, test.want)
+		}
+	}
+}
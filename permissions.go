@@ -0,0 +1,41 @@
+package discordgo
+
+//Permissions is a set of permission bits used by roles and permission overwrites
+type Permissions int
+
+const (
+	PERMISSION_CREATE_INSTANT_INVITE Permissions = 1 << 0
+	PERMISSION_KICK_MEMBERS          Permissions = 1 << 1
+	PERMISSION_BAN_MEMBERS           Permissions = 1 << 2
+	PERMISSION_ADMINISTRATOR         Permissions = 1 << 3
+	PERMISSION_MANAGE_CHANNELS       Permissions = 1 << 4
+	PERMISSION_MANAGE_GUILD          Permissions = 1 << 5
+	PERMISSION_READ_MESSAGES         Permissions = 1 << 10
+	PERMISSION_SEND_MESSAGES         Permissions = 1 << 11
+	PERMISSION_SEND_TTS_MESSAGES     Permissions = 1 << 12
+	PERMISSION_MANAGE_MESSAGES       Permissions = 1 << 13
+	PERMISSION_EMBED_LINKS           Permissions = 1 << 14
+	PERMISSION_ATTACH_FILES          Permissions = 1 << 15
+	PERMISSION_READ_MESSAGE_HISTORY  Permissions = 1 << 16
+	PERMISSION_MENTION_EVERYONE      Permissions = 1 << 17
+	PERMISSION_VOICE_CONNECT         Permissions = 1 << 20
+	PERMISSION_VOICE_SPEAK           Permissions = 1 << 21
+	PERMISSION_VOICE_MUTE_MEMBERS    Permissions = 1 << 22
+	PERMISSION_VOICE_DEAFEN_MEMBERS  Permissions = 1 << 23
+	PERMISSION_VOICE_MOVE_MEMBERS    Permissions = 1 << 24
+	PERMISSION_VOICE_USE_VAD         Permissions = 1 << 25
+	PERMISSION_MANAGE_ROLES          Permissions = 1 << 28
+)
+
+//Has returns true if all bits of perm are set
+func (p Permissions) Has(perm Permissions) bool {
+	return p&perm == perm
+}
+
+func (p Permissions) Add(perm Permissions) Permissions {
+	return p | perm
+}
+
+func (p Permissions) Remove(perm Permissions) Permissions {
+	return p &^ perm
+}
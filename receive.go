@@ -0,0 +1,188 @@
+package discordgo
+
+import (
+	"sort"
+	"sync"
+)
+
+//number of packets a stream holds back to put packets that arrive out of order in place, 5 packets are 100ms
+const jitterBufferDepth = 5
+
+//rtp payload type of the opus frames, rtcp packets arriving on the same socket have a different one
+const rtpPayloadOpus = 0x78
+
+//VoiceDecrypter decrypts the payload of an incoming voice packet. Discord uses xsalsa20_poly1305 with the
+//session key and the raw rtp header padded to 24 bytes as nonce, the voice connection has to provide it
+type VoiceDecrypter interface {
+	Open(header []byte, payload []byte) ([]byte, error)
+}
+
+//VoiceSpeakingUpdate is sent over the voice websocket when a user starts or stops speaking,
+//it is the only place that tells which user sends with an ssrc
+type VoiceSpeakingUpdate struct {
+	UserID   string `json:"user_id"`
+	SSRC     uint32 `json:"ssrc"`
+	Speaking bool   `json:"speaking"`
+}
+
+//VoicePacket is one opus frame of a user, Lost is the number of frames missing right before it.
+//UserID is empty if no speaking update for the ssrc was received yet
+type VoicePacket struct {
+	SSRC      uint32
+	UserID    string
+	Sequence  uint16
+	Timestamp uint32
+	Opus      []byte
+	Lost      int
+}
+
+//VoiceStream delivers the frames of one ssrc in sequence order
+type VoiceStream struct {
+	SSRC    uint32
+	Packets chan *VoicePacket
+	buffer  jitterBuffer
+}
+
+//VoiceReceiver decrypts incoming voice packets and sorts them into one stream per ssrc.
+//Every new stream is sent on Streams, the streams and Streams have to be read or HandlePacket blocks
+type VoiceReceiver struct {
+	Streams   chan *VoiceStream
+	decrypter VoiceDecrypter
+	mut       sync.Mutex
+	users     map[uint32]string
+	streams   map[uint32]*VoiceStream
+	closed    bool
+}
+
+func NewVoiceReceiver(decrypter VoiceDecrypter) *VoiceReceiver {
+	return &VoiceReceiver{
+		Streams:   make(chan *VoiceStream, 16),
+		decrypter: decrypter,
+		users:     make(map[uint32]string),
+		streams:   make(map[uint32]*VoiceStream),
+	}
+}
+
+//HandleSpeaking remembers the user of the ssrc, packets received before are delivered without user id
+func (r *VoiceReceiver) HandleSpeaking(update VoiceSpeakingUpdate) {
+	r.mut.Lock()
+	r.users[update.SSRC] = update.UserID
+	r.mut.Unlock()
+}
+
+//UserID returns the user sending with the ssrc, empty if it is not known yet
+func (r *VoiceReceiver) UserID(ssrc uint32) string {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	return r.users[ssrc]
+}
+
+//HandlePacket decrypts a packet read from the voice udp socket and delivers its frame on the stream of its ssrc,
+//packets that are not opus are ignored
+func (r *VoiceReceiver) HandlePacket(packet []byte) error {
+	header, raw, payload, err := ParseRTPHeader(packet)
+	if err != nil {
+		return err
+	}
+	if packet[1]&0x7F != rtpPayloadOpus {
+		return nil
+	}
+	opus, err := r.decrypter.Open(raw, payload)
+	if err != nil {
+		return err
+	}
+
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	if r.closed {
+		return nil
+	}
+	stream, exists := r.streams[header.SSRC]
+	if !exists {
+		stream = &VoiceStream{SSRC: header.SSRC, Packets: make(chan *VoicePacket, 64), buffer: jitterBuffer{depth: jitterBufferDepth}}
+		r.streams[header.SSRC] = stream
+		r.Streams <- stream
+	}
+	for _, p := range stream.buffer.push(&VoicePacket{
+		SSRC:      header.SSRC,
+		UserID:    r.users[header.SSRC],
+		Sequence:  header.Sequence,
+		Timestamp: header.Timestamp,
+		Opus:      opus,
+	}) {
+		stream.Packets <- p
+	}
+	return nil
+}
+
+//Close delivers the frames still held back and closes every stream and Streams
+func (r *VoiceReceiver) Close() {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	if r.closed {
+		return
+	}
+	r.closed = true
+	for _, stream := range r.streams {
+		for _, p := range stream.buffer.flush() {
+			stream.Packets <- p
+		}
+		close(stream.Packets)
+	}
+	close(r.Streams)
+}
+
+//jitterBuffer puts the packets of one ssrc back in sequence order. A packet is released as soon as it is the next
+//one in sequence, or when more than depth packets are held back, the frames skipped then are counted as lost
+type jitterBuffer struct {
+	depth   int
+	next    uint16
+	started bool
+	packets []*VoicePacket
+}
+
+//before reports whether sequence a comes before b, sequences wrap around after 65535
+func before(a uint16, b uint16) bool {
+	return int16(a-b) < 0
+}
+
+//push adds the packet and returns the packets that are ready in sequence order,
+//duplicates and packets arriving after their place was skipped are dropped
+func (j *jitterBuffer) push(packet *VoicePacket) (ready []*VoicePacket) {
+	if j.started && before(packet.Sequence, j.next) {
+		return nil
+	}
+	i := sort.Search(len(j.packets), func(i int) bool {
+		return !before(j.packets[i].Sequence, packet.Sequence)
+	})
+	if i < len(j.packets) && j.packets[i].Sequence == packet.Sequence {
+		return nil
+	}
+	j.packets = append(j.packets, nil)
+	copy(j.packets[i+1:], j.packets[i:])
+	j.packets[i] = packet
+
+	for len(j.packets) > j.depth || (len(j.packets) > 0 && j.started && j.packets[0].Sequence == j.next) {
+		ready = append(ready, j.pop())
+	}
+	return
+}
+
+//flush returns every packet held back
+func (j *jitterBuffer) flush() (ready []*VoicePacket) {
+	for len(j.packets) > 0 {
+		ready = append(ready, j.pop())
+	}
+	return
+}
+
+func (j *jitterBuffer) pop() *VoicePacket {
+	packet := j.packets[0]
+	j.packets = j.packets[1:]
+	if j.started {
+		packet.Lost = int(packet.Sequence - j.next)
+	}
+	j.started = true
+	j.next = packet.Sequence + 1
+	return packet
+}
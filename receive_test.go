@@ -0,0 +1,111 @@
+package discordgo
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testDecrypter struct{}
+
+func (testDecrypter) Open(header []byte, payload []byte) ([]byte, error) {
+	if len(payload) == 0 {
+		return nil, errors.New("authentication failed")
+	}
+	return payload, nil
+}
+
+func TestJitterBuffer(t *testing.T) {
+	type released struct {
+		Sequence uint16
+		Lost     int
+	}
+	tests := []struct {
+		name      string
+		sequences []uint16
+		want      []released
+	}{
+		{"in order", []uint16{1, 2, 3, 4}, []released{{1, 0}, {2, 0}, {3, 0}, {4, 0}}},
+		{"reordered", []uint16{1, 3, 2, 4}, []released{{1, 0}, {2, 0}, {3, 0}, {4, 0}}},
+		{"gap", []uint16{1, 2, 5, 6}, []released{{1, 0}, {2, 0}, {5, 2}, {6, 0}}},
+		{"duplicate", []uint16{1, 2, 2, 3}, []released{{1, 0}, {2, 0}, {3, 0}}},
+		{"late after the gap was skipped", []uint16{1, 2, 4, 5, 6, 3, 7}, []released{{1, 0}, {2, 0}, {4, 1}, {5, 0}, {6, 0}, {7, 0}}},
+		{"wrap around", []uint16{65534, 0, 65535, 1}, []released{{65534, 0}, {65535, 0}, {0, 0}, {1, 0}}},
+	}
+	for _, test := range tests {
+		j := jitterBuffer{depth: 2}
+		var got []released
+		for _, sequence := range test.sequences {
+			for _, p := range j.push(&VoicePacket{Sequence: sequence}) {
+				got = append(got, released{p.Sequence, p.Lost})
+			}
+		}
+		for _, p := range j.flush() {
+			got = append(got, released{p.Sequence, p.Lost})
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestParseRTPHeader(t *testing.T) {
+	packet := append(RTPHeader{Sequence: 7, Timestamp: 960, SSRC: 42}.Bytes(), 1, 2)
+	header, raw, payload, err := ParseRTPHeader(packet)
+	if err != nil || header != (RTPHeader{Sequence: 7, Timestamp: 960, SSRC: 42}) ||
+		!bytes.Equal(raw, packet[:RTP_HEADER_SIZE]) || !bytes.Equal(payload, []byte{1, 2}) {
+		t.Errorf("got %+v %x %x %v", header, raw, payload, err)
+	}
+	withCSRC := append([]byte{0x81}, packet[1:RTP_HEADER_SIZE]...)
+	withCSRC = append(withCSRC, 0, 0, 0, 9, 3)
+	if _, _, payload, err := ParseRTPHeader(withCSRC); err != nil || !bytes.Equal(payload, []byte{3}) {
+		t.Errorf("contributing source was not skipped: %x %v", payload, err)
+	}
+	for _, invalid := range [][]byte{packet[:8], append([]byte{0x40}, packet[1:]...), withCSRC[:14]} {
+		if _, _, _, err := ParseRTPHeader(invalid); err == nil {
+			t.Errorf("%x was parsed", invalid)
+		}
+	}
+}
+
+func TestVoiceReceiver(t *testing.T) {
+	r := NewVoiceReceiver(testDecrypter{})
+	r.HandleSpeaking(VoiceSpeakingUpdate{UserID: "2", SSRC: 42, Speaking: true})
+
+	packet := func(ssrc uint32, sequence uint16) []byte {
+		return append(RTPHeader{Sequence: sequence, SSRC: ssrc}.Bytes(), byte(sequence))
+	}
+	for _, p := range [][]byte{packet(42, 1), packet(43, 1), packet(42, 3), packet(42, 2)} {
+		if err := r.HandlePacket(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rtcp := packet(42, 4)
+	rtcp[1] = 0xC9
+	if err := r.HandlePacket(rtcp); err != nil {
+		t.Errorf("rtcp packet was not ignored: %v", err)
+	}
+	if err := r.HandlePacket(RTPHeader{SSRC: 42}.Bytes()); err == nil {
+		t.Errorf("decryption error was not returned")
+	}
+	r.Close()
+
+	streams := map[uint32][]*VoicePacket{}
+	for stream := range r.Streams {
+		for p := range stream.Packets {
+			streams[stream.SSRC] = append(streams[stream.SSRC], p)
+		}
+	}
+	if len(streams[42]) != 3 || len(streams[43]) != 1 {
+		t.Fatalf("got %d and %d packets", len(streams[42]), len(streams[43]))
+	}
+	for i, p := range streams[42] {
+		if p.Sequence != uint16(i+1) || p.UserID != "2" || !bytes.Equal(p.Opus, []byte{byte(i + 1)}) {
+			t.Errorf("packet %d of ssrc 42 is %+v", i, p)
+		}
+	}
+	if streams[43][0].UserID != "" {
+		t.Errorf("ssrc 43 has user %q without speaking update", streams[43][0].UserID)
+	}
+}
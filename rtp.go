@@ -0,0 +1,75 @@
+package discordgo
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	//samples per channel in a 20ms opus frame at 48kHz, the rtp timestamp advances by this per frame
+	OPUS_FRAME_SIZE = 960
+	RTP_HEADER_SIZE = 12
+)
+
+//opusSilence is sent five times after the last frame so clients don't interpolate the end of the stream
+var opusSilence = []byte{0xF8, 0xFF, 0xFE}
+
+//RTPHeader of a voice packet, the encrypted opus frame follows it
+type RTPHeader struct {
+	Sequence  uint16
+	Timestamp uint32
+	SSRC      uint32
+}
+
+//Bytes returns the 12 byte header with version 2 and the opus payload type
+func (h RTPHeader) Bytes() []byte {
+	by := make([]byte, RTP_HEADER_SIZE)
+	by[0] = 0x80
+	by[1] = 0x78
+	binary.BigEndian.PutUint16(by[2:4], h.Sequence)
+	binary.BigEndian.PutUint32(by[4:8], h.Timestamp)
+	binary.BigEndian.PutUint32(by[8:12], h.SSRC)
+	return by
+}
+
+//RTPSequencer numbers the frames of one outgoing opus stream
+type RTPSequencer struct {
+	header RTPHeader
+}
+
+//NewRTPSequencer starts the sequence and timestamp at random values like RFC 3550 asks for
+func NewRTPSequencer(ssrc uint32) *RTPSequencer {
+	s := &RTPSequencer{header: RTPHeader{SSRC: ssrc}}
+	var start [6]byte
+	if _, err := rand.Read(start[:]); err == nil {
+		s.header.Sequence = binary.BigEndian.Uint16(start[:2])
+		s.header.Timestamp = binary.BigEndian.Uint32(start[2:])
+	}
+	return s
+}
+
+//Next returns the header for the next frame, the sequence and timestamp wrap around like rtp expects
+func (s *RTPSequencer) Next() RTPHeader {
+	header := s.header
+	s.header.Sequence++
+	s.header.Timestamp += OPUS_FRAME_SIZE
+	return header
+}
+
+//ParseRTPHeader returns the header of a voice packet and the payload after it, the raw header is
+//the nonce the payload was encrypted with
+func ParseRTPHeader(packet []byte) (header RTPHeader, raw []byte, payload []byte, err error) {
+	if len(packet) < RTP_HEADER_SIZE || packet[0]>>6 != 2 {
+		return header, nil, nil, errors.New("rtp: not a version 2 packet")
+	}
+	//contributing sources follow the fixed header, discord doesn't send any but they are skipped anyway
+	size := RTP_HEADER_SIZE + 4*int(packet[0]&0x0F)
+	if len(packet) < size {
+		return header, nil, nil, errors.New("rtp: packet shorter than its header")
+	}
+	header.Sequence = binary.BigEndian.Uint16(packet[2:4])
+	header.Timestamp = binary.BigEndian.Uint32(packet[4:8])
+	header.SSRC = binary.BigEndian.Uint32(packet[8:12])
+	return header, packet[:size], packet[size:], nil
+}
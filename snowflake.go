@@ -0,0 +1,127 @@
+package discordgo
+
+import (
+	"strconv"
+	"time"
+)
+
+//milliseconds since the unix epoch of the first second of 2015, the start of discord ids
+const discordEpoch = 1420070400000
+
+//Snowflake is a discord id (guild, channel, user, message...), it contains the time it was created at.
+//Ids are sent as strings, so a Snowflake is marshaled to and from a json string
+type Snowflake uint64
+
+func ParseSnowflake(id string) (Snowflake, error) {
+	v, err := strconv.ParseUint(id, 10, 64)
+	return Snowflake(v), err
+}
+
+//SnowflakeFromTime returns the smallest id that can be created at t, used as before and after cursor
+func SnowflakeFromTime(t time.Time) Snowflake {
+	ms := t.UnixNano()/int64(time.Millisecond) - discordEpoch
+	if ms < 0 {
+		return 0
+	}
+	return Snowflake(uint64(ms) << 22)
+}
+
+func (s Snowflake) String() string {
+	return strconv.FormatUint(uint64(s), 10)
+}
+
+//Time returns the time the id was created at
+func (s Snowflake) Time() time.Time {
+	ms := int64(s>>22) + discordEpoch
+	return time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond))
+}
+
+func (s Snowflake) WorkerID() int {
+	return int((s >> 17) & 0x1F)
+}
+
+func (s Snowflake) ProcessID() int {
+	return int((s >> 12) & 0x1F)
+}
+
+//Increment is the number of ids created by the process before this one in the same millisecond
+func (s Snowflake) Increment() int {
+	return int(s & 0xFFF)
+}
+
+func (s Snowflake) Before(other Snowflake) bool {
+	return s < other
+}
+
+func (s Snowflake) After(other Snowflake) bool {
+	return s > other
+}
+
+//Compare returns -1 if s is older than other, 1 if it is newer and 0 if both are the same
+func (s Snowflake) Compare(other Snowflake) int {
+	switch {
+	case s < other:
+		return -1
+	case s > other:
+		return 1
+	}
+	return 0
+}
+
+func (s Snowflake) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(s.String())), nil
+}
+
+//UnmarshalJSON accepts ids as string or number, null leaves the Snowflake unchanged and an empty string sets it to 0
+func (s *Snowflake) UnmarshalJSON(by []byte) error {
+	str := string(by)
+	if str == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(str); err == nil {
+		str = unquoted
+	}
+	if str == "" {
+		*s = 0
+		return nil
+	}
+	v, err := ParseSnowflake(str)
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+//createdAt returns the time the id was created at, the zero time for ids that are not snowflakes
+func createdAt(id string) time.Time {
+	s, err := ParseSnowflake(id)
+	if err != nil {
+		return time.Time{}
+	}
+	return s.Time()
+}
+
+func (m Message) CreatedAt() time.Time {
+	return createdAt(m.ID)
+}
+
+func (u User) CreatedAt() time.Time {
+	return createdAt(u.ID)
+}
+
+func (c Channel) CreatedAt() time.Time {
+	return createdAt(c.ID)
+}
+
+func (c PrivateChannel) CreatedAt() time.Time {
+	return createdAt(c.ID)
+}
+
+func (g Guild) CreatedAt() time.Time {
+	return createdAt(g.ID)
+}
+
+func (r Role) CreatedAt() time.Time {
+	return createdAt(r.ID)
+}
@@ -0,0 +1,71 @@
+package discordgo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+//175928847299117063 is the example id of the discord documentation
+const exampleID Snowflake = 175928847299117063
+
+func TestSnowflakeFields(t *testing.T) {
+	want := time.Date(2016, 4, 30, 11, 18, 25, 796*int(time.Millisecond), time.UTC)
+	if got := exampleID.Time(); !got.Equal(want) {
+		t.Errorf("Time is %v, want %v", got, want)
+	}
+	if got := exampleID.WorkerID(); got != 1 {
+		t.Errorf("WorkerID is %d, want 1", got)
+	}
+	if got := exampleID.ProcessID(); got != 0 {
+		t.Errorf("ProcessID is %d, want 0", got)
+	}
+	if got := exampleID.Increment(); got != 7 {
+		t.Errorf("Increment is %d, want 7", got)
+	}
+	if from := SnowflakeFromTime(want); !from.Time().Equal(want) || from.After(exampleID) {
+		t.Errorf("SnowflakeFromTime returned %v", from)
+	}
+	if got := (Message{ID: exampleID.String()}).CreatedAt(); !got.Equal(want) {
+		t.Errorf("CreatedAt is %v, want %v", got, want)
+	}
+	if got := (User{ID: "not an id"}).CreatedAt(); !got.IsZero() {
+		t.Errorf("CreatedAt of an invalid id is %v", got)
+	}
+}
+
+func TestSnowflakeJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Snowflake
+	}{
+		{`{"id":"175928847299117063"}`, exampleID},
+		{`{"id":175928847299117063}`, exampleID},
+		{`{"id":""}`, 0},
+		{`{"id":null}`, 0},
+	}
+	for _, test := range tests {
+		var v struct {
+			ID Snowflake `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(test.input), &v); err != nil || v.ID != test.want {
+			t.Errorf("%s: got %v (%v), want %v", test.input, v.ID, err, test.want)
+		}
+	}
+
+	by, err := json.Marshal(struct {
+		ID Snowflake `json:"id"`
+	}{exampleID})
+	if err != nil || string(by) != `{"id":"175928847299117063"}` {
+		t.Errorf("marshaled to %s (%v)", by, err)
+	}
+	var back struct {
+		ID Snowflake `json:"id"`
+	}
+	if err := json.Unmarshal(by, &back); err != nil || back.ID != exampleID {
+		t.Errorf("round trip returned %v (%v)", back.ID, err)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"abc"}`), &back); err == nil {
+		t.Error("expected an error for an invalid id")
+	}
+}
@@ -2,8 +2,14 @@ package discordgo
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"reflect"
+	"strings"
 	"time"
+
+	"github.com/Kemonozume/discordgo/markdown"
 )
 
 const (
@@ -13,8 +19,32 @@ const (
 	EVENT_PRESENCE_UPDATE     = "PRESENCE_UPDATE"
 	EVENT_MESSAGE_CREATE      = "MESSAGE_CREATE"
 	EVENT_READY               = "READY"
+	EVENT_USER_UPDATE         = "USER_UPDATE"
+	EVENT_CHANNEL_CREATE      = "CHANNEL_CREATE"
 	EVENT_CHANNEL_UPDATE      = "CHANNEL_UPDATE"
+	EVENT_CHANNEL_DELETE      = "CHANNEL_DELETE"
+	EVENT_GUILD_CREATE        = "GUILD_CREATE"
 	EVENT_GUILD_UPDATE        = "GUILD_UPDATE"
+	EVENT_GUILD_DELETE        = "GUILD_DELETE"
+	EVENT_GUILD_BAN_ADD       = "GUILD_BAN_ADD"
+	EVENT_GUILD_BAN_REMOVE    = "GUILD_BAN_REMOVE"
+	EVENT_GUILD_ROLE_CREATE   = "GUILD_ROLE_CREATE"
+	EVENT_GUILD_ROLE_UPDATE   = "GUILD_ROLE_UPDATE"
+	EVENT_GUILD_ROLE_DELETE   = "GUILD_ROLE_DELETE"
+	EVENT_VOICE_STATE_UPDATE  = "VOICE_STATE_UPDATE"
+	EVENT_TYPING_START        = "TYPING_START"
+	EVENT_CHANNEL_PINS_UPDATE = "CHANNEL_PINS_UPDATE"
+
+	//not sent by discord, derived from VOICE_STATE_UPDATE
+	EVENT_VOICE_JOIN  = "VOICE_JOIN"
+	EVENT_VOICE_LEAVE = "VOICE_LEAVE"
+	EVENT_VOICE_MOVE  = "VOICE_MOVE"
+
+	//not sent by discord, derived from READY, GUILD_CREATE and GUILD_DELETE.
+	//GUILD_READY fires once per guild, use GUILD_AVAILABLE to see guilds coming back from an outage
+	EVENT_GUILD_READY       = "GUILD_READY"
+	EVENT_GUILD_AVAILABLE   = "GUILD_AVAILABLE"
+	EVENT_GUILD_UNAVAILABLE = "GUILD_UNAVAILABLE"
 )
 
 //GATEWAY RESPONSE STRUCT
@@ -49,17 +79,17 @@ type dReadyMessage struct {
 	Op int    `json:"op"`
 	D  struct {
 		V                 int              `json:"v"`
-		User              dROurUser        `json:"user"`
+		User              BotUser          `json:"user"`
 		SessionID         string           `json:"session_id"`
 		ReadState         []readState      `json:"read_state"`
-		PrivateChannels   []privateChannel `json:"private_channels"`
+		PrivateChannels   []PrivateChannel `json:"private_channels"`
 		HeartbeatInterval int              `json:"heartbeat_interval"`
 		Guilds            []Guild          `json:"guilds"`
 	} `json:"d"`
 }
 
-//BOT USER
-type dROurUser struct {
+//BOT USER, the user the bot is logged in as
+type BotUser struct {
 	Verified      bool   `json:"verified"`
 	Username      string `json:"username"`
 	ID            string `json:"id"`
@@ -74,46 +104,61 @@ type readState struct {
 	ID            string `json:"id"`
 }
 
-type privateChannel struct {
-	Recipient     recipient `json:"recipient"`
-	LastMessageID string    `json:"last_message_id"`
-	IsPrivate     bool      `json:"is_private"`
-	ID            string    `json:"id"`
-}
-
-type recipient struct {
-	Username      string `json:"username"`
+//PrivateChannel is a direct message channel with a single user
+type PrivateChannel struct {
+	Recipient     User   `json:"recipient"`
+	LastMessageID string `json:"last_message_id"`
+	IsPrivate     bool   `json:"is_private"`
 	ID            string `json:"id"`
-	Discriminator string `json:"discriminator"`
-	Avatar        string `json:"avatar"`
 }
 
 //Guild struct (contains members, member Status and channels
 type Guild struct {
-	VoiceStates  []interface{} `json:"voice_states"`
-	Roles        []Role        `json:"roles"`
-	Region       string        `json:"region"`
-	Presences    []Presence    `json:"presences"`
-	OwnerID      string        `json:"owner_id"`
-	Name         string        `json:"name"`
-	Members      []Member      `json:"members"`
-	JoinedAt     time.Time     `json:"joined_at"`
-	ID           string        `json:"id"`
-	Icon         string        `json:"icon"`
-	Channels     []Channel     `json:"channels"`
-	AfkTimeout   int           `json:"afk_timeout"`
-	AfkChannelID interface{}   `json:"afk_channel_id"`
+	VoiceStates  []VoiceState `json:"voice_states"`
+	Roles        []Role       `json:"roles"`
+	Region       string       `json:"region"`
+	Presences    []Presence   `json:"presences"`
+	OwnerID      string       `json:"owner_id"`
+	Name         string       `json:"name"`
+	Members      []Member     `json:"members"`
+	JoinedAt     time.Time    `json:"joined_at"`
+	ID           string       `json:"id"`
+	Icon         string       `json:"icon"`
+	Channels     []Channel    `json:"channels"`
+	AfkTimeout   int          `json:"afk_timeout"`
+	AfkChannelID interface{}  `json:"afk_channel_id"`
+	Unavailable  bool         `json:"unavailable"`
+}
+
+type GuildFunction func(Guild, *DiscordBot)
+
+//UserGuild is the short version of a guild returned by GetUserGuilds
+type UserGuild struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Icon        string      `json:"icon"`
+	Owner       bool        `json:"owner"`
+	Permissions Permissions `json:"permissions"`
 }
 
 type Role struct {
-	Position    int    `json:"position"`
-	Permissions int    `json:"permissions"`
-	Name        string `json:"name"`
-	ID          string `json:"id"`
-	Hoist       bool   `json:"hoist"`
-	Color       int    `json:"color"`
+	Position    int         `json:"position"`
+	Permissions Permissions `json:"permissions"`
+	Name        string      `json:"name"`
+	ID          string      `json:"id"`
+	Hoist       bool        `json:"hoist"`
+	Color       int         `json:"color"`
+}
+
+//RoleChange holds the cached role before and after a role event, Old is empty for created and New for deleted roles
+type RoleChange struct {
+	GuildID string
+	Old     Role
+	New     Role
 }
 
+type RoleFunction func(RoleChange, *DiscordBot)
+
 type Presence struct {
 	User   User        `json:"user"`
 	Status string      `json:"status"`
@@ -125,12 +170,39 @@ type User struct {
 	ID            string      `json:"id"`
 	Discriminator json.Number `json:"discriminator,Number"`
 	Avatar        string      `json:"avatar"`
+	Bot           bool        `json:"bot"`
 }
 
 func (d User) Mention() string {
 	return fmt.Sprintf("<@%v>", d.ID)
 }
 
+type Ban struct {
+	User   User   `json:"user"`
+	Reason string `json:"reason"`
+}
+
+//GuildBan is sent with GUILD_BAN_ADD and GUILD_BAN_REMOVE
+type GuildBan struct {
+	User    User   `json:"user"`
+	GuildID string `json:"guild_id"`
+}
+
+type BanFunction func(GuildBan, *DiscordBot)
+
+type Invite struct {
+	Code      string    `json:"code"`
+	Guild     Guild     `json:"guild"`
+	Channel   Channel   `json:"channel"`
+	Inviter   User      `json:"inviter"`
+	Uses      int       `json:"uses"`
+	MaxUses   int       `json:"max_uses"`
+	MaxAge    int       `json:"max_age"`
+	Temporary bool      `json:"temporary"`
+	Revoked   bool      `json:"revoked"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Member struct {
 	User     User      `json:"user"`
 	Roles    []string  `json:"roles"`
@@ -140,41 +212,69 @@ type Member struct {
 }
 
 type Channel struct {
-	Type                 string                   `json:"type"`
-	Topic                string                   `json:"topic"`
-	Position             int                      `json:"position"`
-	PermissionOverwrites []dRPermissionOverwrites `json:"permission_overwrites"`
-	Name                 string                   `json:"name"`
-	LastMessageID        string                   `json:"last_message_id"`
-	ID                   string                   `json:"id"`
+	Type                 string                `json:"type"`
+	Topic                string                `json:"topic"`
+	Position             int                   `json:"position"`
+	PermissionOverwrites []PermissionOverwrite `json:"permission_overwrites"`
+	Name                 string                `json:"name"`
+	LastMessageID        string                `json:"last_message_id"`
+	IsPrivate            bool                  `json:"is_private"`
+	ID                   string                `json:"id"`
+	GuildID              string                `json:"guild_id"`
 }
 
-type dRPermissionOverwrites struct {
-	Type  string `json:"type"`
-	ID    string `json:"id"`
-	Deny  int    `json:"deny"`
-	Allow int    `json:"allow"`
+type ChannelFunction func(Channel, *DiscordBot)
+
+const (
+	CHANNEL_TYPE_TEXT  = "text"
+	CHANNEL_TYPE_VOICE = "voice"
+)
+
+//PermissionOverwrite of a channel, Type is "role" or "member" and ID the id of the role or member
+type PermissionOverwrite struct {
+	Type  string      `json:"type"`
+	ID    string      `json:"id"`
+	Deny  Permissions `json:"deny"`
+	Allow Permissions `json:"allow"`
 }
 
 //MESSAGE_CREATE
 type MessageResponse struct {
-	Op int    `json:"op"`
-	S  int    `json:"s"`
-	T  string `json:"t"`
-	D  struct {
-		Attachments     []interface{} `json:"attachments"`
-		Author          User          `json:"author"`
-		ChannelID       string        `json:"channel_id"`
-		Content         string        `json:"content"`
-		EditedTimestamp interface{}   `json:"edited_timestamp"`
-		Embeds          []interface{} `json:"embeds"`
-		ID              string        `json:"id"`
-		MentionEveryone bool          `json:"mention_everyone"`
-		Mentions        []User        `json:"mentions"`
-		Nonce           string        `json:"nonce"`
-		Timestamp       string        `json:"timestamp"`
-		Tts             bool          `json:"tts"`
-	} `json:"d"`
+	Op int     `json:"op"`
+	S  int     `json:"s"`
+	T  string  `json:"t"`
+	D  Message `json:"d"`
+}
+
+type Message struct {
+	Attachments     []Attachment `json:"attachments"`
+	Author          User         `json:"author"`
+	ChannelID       string       `json:"channel_id"`
+	Content         string       `json:"content"`
+	EditedTimestamp *time.Time   `json:"edited_timestamp"`
+	Embeds          []Embed      `json:"embeds"`
+	GuildID         string       `json:"guild_id"`
+	ID              string       `json:"id"`
+	MentionEveryone bool         `json:"mention_everyone"`
+	Mentions        []User       `json:"mentions"`
+	Nonce           string       `json:"nonce"`
+	Timestamp       time.Time    `json:"timestamp"`
+	Tts             bool         `json:"tts"`
+	IsPrivate       bool         `json:"-"`
+	//the channel is neither a cached private channel nor one of a cached guild, IsPrivate is false then.
+	//The CHANNEL_CREATE of a new channel or the GUILD_CREATE of an unavailable guild may not be handled yet
+	ChannelUnknown bool `json:"-"`
+}
+
+//Attachment of a message, Width and Height are only set for images
+type Attachment struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	Size     int    `json:"size"`
+	URL      string `json:"url"`
+	ProxyURL string `json:"proxy_url"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
 }
 
 //Message_Send
@@ -182,6 +282,7 @@ type MessageRequest struct {
 	Content  string   `json:"content"`
 	Mentions []string `json:"mentions"`
 	Tts      bool     `json:"tts"`
+	Embed    *Embed   `json:"embed,omitempty"`
 }
 
 func (d *MessageRequest) AddMention(user User) {
@@ -192,6 +293,42 @@ func NewMessage(content string) MessageRequest {
 	return MessageRequest{Content: content}
 }
 
+//NewMarkdownMessage formats the message like fmt.Sprintf, but escapes the markdown in every string argument,
+//so user supplied text like usernames can't break the formatting of the message. Arguments of type markdown.Raw are not escaped
+func NewMarkdownMessage(format string, args ...interface{}) MessageRequest {
+	escaped := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case markdown.Raw:
+			escaped[i] = string(v)
+		case string:
+			escaped[i] = markdown.Escape(v)
+		case fmt.Stringer:
+			escaped[i] = markdown.Escape(v.String())
+		default:
+			escaped[i] = arg
+		}
+	}
+	return NewMessage(fmt.Sprintf(format, escaped...))
+}
+
+//TypingStart is sent when a user starts typing in a channel, Timestamp is in unix seconds
+type TypingStart struct {
+	UserID    string `json:"user_id"`
+	ChannelID string `json:"channel_id"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type TypingFunction func(TypingStart, *DiscordBot)
+
+//ChannelPinsUpdate is sent when a message is pinned or unpinned, LastPinTimestamp is zero if no message is pinned
+type ChannelPinsUpdate struct {
+	ChannelID        string    `json:"channel_id"`
+	LastPinTimestamp time.Time `json:"last_pin_timestamp"`
+}
+
+type ChannelPinsFunction func(ChannelPinsUpdate, *DiscordBot)
+
 //Login Message
 type loginMessage struct {
 	Email    string `json:"email"`
@@ -248,22 +385,12 @@ type dPUMessage struct {
 	} `json:"d"`
 }
 
-//Channel Update Message
-type dCUMessage struct {
-	T  string `json:"t"`
-	S  int    `json:"s"`
-	Op int    `json:"op"`
-	D  struct {
-		Type                 string                   `json:"type"`
-		Topic                string                   `json:"topic"`
-		Position             int                      `json:"position"`
-		PermissionOverwrites []dRPermissionOverwrites `json:"permission_overwrites"`
-		Name                 string                   `json:"name"`
-		LastMessageID        string                   `json:"last_message_id"`
-		IsPrivate            bool                     `json:"is_private"`
-		ID                   string                   `json:"id"`
-		GuildID              string                   `json:"guild_id"`
-	} `json:"d"`
+//Channel Create, Update and Delete Message
+type dCMessage struct {
+	T  string  `json:"t"`
+	S  int     `json:"s"`
+	Op int     `json:"op"`
+	D  Channel `json:"d"`
 }
 
 //Guild Update Message
@@ -286,18 +413,253 @@ type dGUMessage struct {
 	} `json:"d"`
 }
 
-//Channel Update Request
+//Private Channel Create and Delete message
+type dPCMessage struct {
+	T  string         `json:"t"`
+	S  int            `json:"s"`
+	Op int            `json:"op"`
+	D  PrivateChannel `json:"d"`
+}
+
+//Typing Start message
+type dTSMessage struct {
+	T  string      `json:"t"`
+	S  int         `json:"s"`
+	Op int         `json:"op"`
+	D  TypingStart `json:"d"`
+}
+
+//Channel Pins Update message
+type dCPUMessage struct {
+	T  string            `json:"t"`
+	S  int               `json:"s"`
+	Op int               `json:"op"`
+	D  ChannelPinsUpdate `json:"d"`
+}
+
+//User Update message
+type dUUMessage struct {
+	T  string  `json:"t"`
+	S  int     `json:"s"`
+	Op int     `json:"op"`
+	D  BotUser `json:"d"`
+}
+
+//Guild Create message
+type dGCMessage struct {
+	T  string `json:"t"`
+	S  int    `json:"s"`
+	Op int    `json:"op"`
+	D  Guild  `json:"d"`
+}
+
+//Guild Delete message, unavailable is true if the guild is affected by an outage
+type dGDMessage struct {
+	T  string `json:"t"`
+	S  int    `json:"s"`
+	Op int    `json:"op"`
+	D  struct {
+		ID          string `json:"id"`
+		Unavailable bool   `json:"unavailable"`
+	} `json:"d"`
+}
+
+//Guild Ban Add and Remove message
+type dGBMessage struct {
+	T  string   `json:"t"`
+	S  int      `json:"s"`
+	Op int      `json:"op"`
+	D  GuildBan `json:"d"`
+}
+
+//Guild Role Create, Update and Delete message
+type dGRMessage struct {
+	T  string `json:"t"`
+	S  int    `json:"s"`
+	Op int    `json:"op"`
+	D  struct {
+		GuildID string `json:"guild_id"`
+		Role    Role   `json:"role"`
+		RoleID  string `json:"role_id"`
+	} `json:"d"`
+}
+
+//Channel Update Request, only the fields set with the Set functions are sent
 type ChannelUpdateRequest struct {
-	Name     string `json:"name"`
-	Position int    `json:"position"`
-	Topic    string `json:"topic"`
+	fields map[string]interface{}
 }
 
-//Server Update Request
+func NewChannelUpdate() *ChannelUpdateRequest {
+	return &ChannelUpdateRequest{}
+}
+
+//ChannelPatch returns an update containing only the fields that differ between the cached and the modified channel.
+//The type of a channel can't be changed, a modified type is returned as error
+func ChannelPatch(cached Channel, modified Channel) (*ChannelUpdateRequest, error) {
+	c := NewChannelUpdate()
+	if cached.Type != modified.Type {
+		return c, errors.New("the type of channel " + cached.ID + " can't be changed")
+	}
+	if cached.Name != modified.Name {
+		c.SetName(modified.Name)
+	}
+	if cached.Position != modified.Position {
+		c.SetPosition(modified.Position)
+	}
+	if cached.Topic != modified.Topic {
+		c.SetTopic(modified.Topic)
+	}
+	if !reflect.DeepEqual(cached.PermissionOverwrites, modified.PermissionOverwrites) {
+		c.SetPermissionOverwrites(modified.PermissionOverwrites)
+	}
+	return c, nil
+}
+
+func (c *ChannelUpdateRequest) SetName(name string) *ChannelUpdateRequest {
+	c.set("name", name)
+	return c
+}
+
+func (c *ChannelUpdateRequest) SetPosition(position int) *ChannelUpdateRequest {
+	c.set("position", position)
+	return c
+}
+
+func (c *ChannelUpdateRequest) SetTopic(topic string) *ChannelUpdateRequest {
+	c.set("topic", topic)
+	return c
+}
+
+//SetPermissionOverwrites replaces all permission overwrites of the channel
+func (c *ChannelUpdateRequest) SetPermissionOverwrites(overwrites []PermissionOverwrite) *ChannelUpdateRequest {
+	if overwrites == nil {
+		//null is not accepted, an empty list removes all overwrites
+		overwrites = []PermissionOverwrite{}
+	}
+	c.set("permission_overwrites", overwrites)
+	return c
+}
+
+func (c *ChannelUpdateRequest) set(key string, value interface{}) {
+	if c.fields == nil {
+		c.fields = make(map[string]interface{})
+	}
+	c.fields[key] = value
+}
+
+func (c *ChannelUpdateRequest) IsEmpty() bool {
+	return len(c.fields) == 0
+}
+
+func (c *ChannelUpdateRequest) MarshalJSON() ([]byte, error) {
+	if c.fields == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(c.fields)
+}
+
+//Server Update Request, only the fields set with the Set functions are sent
 type ServerUpdateRequest struct {
-	Name         string      `json:"name"`
-	Icon         string      `json:"icon"`
-	Region       string      `json:"region"`
-	AfkChannelID interface{} `json:"afk_channel_id"`
-	AfkTimeout   int         `json:"afk_timeout"`
+	fields map[string]interface{}
+}
+
+func NewServerUpdate() *ServerUpdateRequest {
+	return &ServerUpdateRequest{}
+}
+
+//ServerPatch returns an update containing only the fields that differ between the cached and the modified guild.
+//The cached icon is a hash, so a modified icon has to be a data uri like the ones EncodeImage returns or empty to remove it
+func ServerPatch(cached Guild, modified Guild) (*ServerUpdateRequest, error) {
+	s := NewServerUpdate()
+	if cached.Name != modified.Name {
+		s.SetName(modified.Name)
+	}
+	if cached.Icon != modified.Icon {
+		switch {
+		case modified.Icon == "":
+			s.ClearIcon()
+		case strings.HasPrefix(modified.Icon, "data:"):
+			s.SetIcon(modified.Icon)
+		default:
+			return s, errors.New("the icon of guild " + cached.ID + " is not a data uri")
+		}
+	}
+	if cached.Region != modified.Region {
+		s.SetRegion(modified.Region)
+	}
+	if cached.AfkChannelID != modified.AfkChannelID {
+		if id, ok := modified.AfkChannelID.(string); ok && id != "" {
+			s.SetAfkChannelID(id)
+		} else {
+			s.ClearAfkChannelID()
+		}
+	}
+	if cached.AfkTimeout != modified.AfkTimeout {
+		s.SetAfkTimeout(modified.AfkTimeout)
+	}
+	return s, nil
+}
+
+func (s *ServerUpdateRequest) SetName(name string) *ServerUpdateRequest {
+	s.set("name", name)
+	return s
+}
+
+func (s *ServerUpdateRequest) SetIcon(icon string) *ServerUpdateRequest {
+	s.set("icon", icon)
+	return s
+}
+
+//ClearIcon sends icon as null, which removes the icon
+func (s *ServerUpdateRequest) ClearIcon() *ServerUpdateRequest {
+	s.set("icon", nil)
+	return s
+}
+
+//SetIconImage encodes the image as data uri and sets it as icon
+func (s *ServerUpdateRequest) SetIconImage(img io.Reader) (*ServerUpdateRequest, error) {
+	icon, err := EncodeImage(img)
+	if err != nil {
+		return s, err
+	}
+	return s.SetIcon(icon), nil
+}
+
+func (s *ServerUpdateRequest) SetRegion(region string) *ServerUpdateRequest {
+	s.set("region", region)
+	return s
+}
+
+func (s *ServerUpdateRequest) SetAfkChannelID(channelid string) *ServerUpdateRequest {
+	s.set("afk_channel_id", channelid)
+	return s
+}
+
+//ClearAfkChannelID sends afk_channel_id as null, which removes the afk channel
+func (s *ServerUpdateRequest) ClearAfkChannelID() *ServerUpdateRequest {
+	s.set("afk_channel_id", nil)
+	return s
+}
+
+func (s *ServerUpdateRequest) SetAfkTimeout(timeout int) *ServerUpdateRequest {
+	s.set("afk_timeout", timeout)
+	return s
+}
+
+func (s *ServerUpdateRequest) set(key string, value interface{}) {
+	if s.fields == nil {
+		s.fields = make(map[string]interface{})
+	}
+	s.fields[key] = value
+}
+
+func (s *ServerUpdateRequest) IsEmpty() bool {
+	return len(s.fields) == 0
+}
+
+func (s *ServerUpdateRequest) MarshalJSON() ([]byte, error) {
+	if s.fields == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(s.fields)
 }
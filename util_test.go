@@ -0,0 +1,115 @@
+package discordgo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Kemonozume/discordgo/markdown"
+)
+
+func TestUpdateRequestJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		update interface{}
+		want   string
+	}{
+		{"empty channel update", NewChannelUpdate(), `{}`},
+		{"empty server update", NewServerUpdate(), `{}`},
+		{"channel name", NewChannelUpdate().SetName("general"), `{"name":"general"}`},
+		{"clear overwrites", NewChannelUpdate().SetPermissionOverwrites(nil), `{"permission_overwrites":[]}`},
+		{"clear afk channel", NewServerUpdate().ClearAfkChannelID(), `{"afk_channel_id":null}`},
+		{"clear icon", NewServerUpdate().ClearIcon(), `{"icon":null}`},
+		{"afk timeout zero", NewServerUpdate().SetAfkTimeout(0), `{"afk_timeout":0}`},
+	}
+	for _, test := range tests {
+		by, err := json.Marshal(test.update)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if string(by) != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, by, test.want)
+		}
+	}
+}
+
+func TestServerPatch(t *testing.T) {
+	cached := Guild{ID: "1", Name: "guild", Icon: "a1b2c3", Region: "us-east", AfkChannelID: "10", AfkTimeout: 300}
+
+	tests := []struct {
+		name     string
+		modified func(Guild) Guild
+		want     string
+		fail     bool
+	}{
+		{"unchanged", func(g Guild) Guild { return g }, `{}`, false},
+		{"name", func(g Guild) Guild { g.Name = "renamed"; return g }, `{"name":"renamed"}`, false},
+		{"clear afk channel", func(g Guild) Guild { g.AfkChannelID = nil; return g }, `{"afk_channel_id":null}`, false},
+		{"clear icon", func(g Guild) Guild { g.Icon = ""; return g }, `{"icon":null}`, false},
+		{"new icon", func(g Guild) Guild { g.Icon = "data:image/png;base64,AAAA"; return g },
+			`{"icon":"data:image/png;base64,AAAA"}`, false},
+		{"icon hash", func(g Guild) Guild { g.Icon = "d4e5f6"; return g }, ``, true},
+	}
+	for _, test := range tests {
+		update, err := ServerPatch(cached, test.modified(cached))
+		if (err != nil) != test.fail {
+			t.Errorf("%s: got error %v", test.name, err)
+			continue
+		}
+		if test.fail {
+			continue
+		}
+		by, _ := json.Marshal(update)
+		if string(by) != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, by, test.want)
+		}
+	}
+}
+
+func TestChannelPatch(t *testing.T) {
+	cached := Channel{ID: "10", Type: CHANNEL_TYPE_TEXT, Name: "general",
+		PermissionOverwrites: []PermissionOverwrite{{Type: "role", ID: "5", Deny: PERMISSION_SEND_MESSAGES}}}
+
+	tests := []struct {
+		name     string
+		modified func(Channel) Channel
+		want     string
+		fail     bool
+	}{
+		{"unchanged", func(c Channel) Channel { return c }, `{}`, false},
+		{"topic", func(c Channel) Channel { c.Topic = "news"; return c }, `{"topic":"news"}`, false},
+		{"overwrites removed", func(c Channel) Channel { c.PermissionOverwrites = nil; return c },
+			`{"permission_overwrites":[]}`, false},
+		{"type", func(c Channel) Channel { c.Type = CHANNEL_TYPE_VOICE; return c }, ``, true},
+	}
+	for _, test := range tests {
+		update, err := ChannelPatch(cached, test.modified(cached))
+		if (err != nil) != test.fail {
+			t.Errorf("%s: got error %v", test.name, err)
+			continue
+		}
+		if test.fail {
+			continue
+		}
+		by, _ := json.Marshal(update)
+		if string(by) != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, by, test.want)
+		}
+	}
+}
+
+func TestNewMarkdownMessage(t *testing.T) {
+	tests := []struct {
+		format string
+		args   []interface{}
+		want   string
+	}{
+		{"%s said %s", []interface{}{"alice", "a_b"}, `alice said a\_b`},
+		{"%s said %s", []interface{}{markdown.Raw(markdown.Bold("alice")), "a_b"}, `**alice** said a\_b`},
+		{"%s has %d points", []interface{}{"*bob*", 3}, `\*bob\* has 3 points`},
+	}
+	for _, test := range tests {
+		if got := NewMarkdownMessage(test.format, test.args...).Content; got != test.want {
+			t.Errorf("%q: got %q, want %q", test.format, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package discordgo
+
+//VoiceState of a user in a guild, ChannelID is empty if the user is not in a voice channel
+type VoiceState struct {
+	UserID    string `json:"user_id"`
+	ChannelID string `json:"channel_id"`
+	GuildID   string `json:"guild_id"`
+	SessionID string `json:"session_id"`
+	SelfMute  bool   `json:"self_mute"`
+	SelfDeaf  bool   `json:"self_deaf"`
+	Mute      bool   `json:"mute"`
+	Deaf      bool   `json:"deaf"`
+	Suppress  bool   `json:"suppress"`
+}
+
+//VoiceStateChange holds the cached voice state before and after a VOICE_STATE_UPDATE
+type VoiceStateChange struct {
+	Before VoiceState
+	After  VoiceState
+}
+
+type VoiceStateFunction func(VoiceStateChange, *DiscordBot)
+
+//Joined returns true if the user was not in a voice channel before
+func (v VoiceStateChange) Joined() bool {
+	return v.Before.ChannelID == "" && v.After.ChannelID != ""
+}
+
+//Left returns true if the user is no longer in a voice channel
+func (v VoiceStateChange) Left() bool {
+	return v.Before.ChannelID != "" && v.After.ChannelID == ""
+}
+
+//Moved returns true if the user switched from one voice channel to another
+func (v VoiceStateChange) Moved() bool {
+	return v.Before.ChannelID != "" && v.After.ChannelID != "" && v.Before.ChannelID != v.After.ChannelID
+}
+
+//returns the derived join, leave or move event, empty if the user stayed in the same channel
+func (v VoiceStateChange) event() string {
+	switch {
+	case v.Joined():
+		return EVENT_VOICE_JOIN
+	case v.Left():
+		return EVENT_VOICE_LEAVE
+	case v.Moved():
+		return EVENT_VOICE_MOVE
+	}
+	return ""
+}
+
+//Voice State Update message
+type dVSUMessage struct {
+	T  string     `json:"t"`
+	S  int        `json:"s"`
+	Op int        `json:"op"`
+	D  VoiceState `json:"d"`
+}
+
+func (d *DiscordBot) updateVoiceState(state VoiceState) (change VoiceStateChange) {
+	d.stateMut.Lock()
+	defer d.stateMut.Unlock()
+	change.After = state
+	guild, index := d.guildById(state.GuildID)
+	if guild.ID != "" {
+		var states []VoiceState
+		for _, vstate := range guild.VoiceStates {
+			if vstate.UserID == state.UserID {
+				change.Before = vstate
+			} else {
+				states = append(states, vstate)
+			}
+		}
+		if state.ChannelID != "" {
+			states = append(states, state)
+		}
+		guild.VoiceStates = states
+		d.Guilds[index] = guild
+	}
+	return
+}
+
+//GetVoiceStatesByChannelId returns the voice states of all users in the voice channel
+func (d *DiscordBot) GetVoiceStatesByChannelId(channelid string) (states []VoiceState) {
+	d.stateMut.RLock()
+	defer d.stateMut.RUnlock()
+	for _, guild := range d.Guilds {
+		for _, state := range guild.VoiceStates {
+			if state.ChannelID == channelid {
+				states = append(states, state)
+			}
+		}
+	}
+	return
+}
+
+//GetVoiceStateByUserId returns the voice state of the user in the guild, exists is false if the user is not in a voice channel
+func (d *DiscordBot) GetVoiceStateByUserId(guildid string, userid string) (state VoiceState, exists bool) {
+	d.stateMut.RLock()
+	defer d.stateMut.RUnlock()
+	guild, _ := d.guildById(guildid)
+	for _, vstate := range guild.VoiceStates {
+		if vstate.UserID == userid {
+			return vstate, true
+		}
+	}
+	return
+}
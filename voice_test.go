@@ -0,0 +1,58 @@
+package discordgo
+
+import "testing"
+
+func TestVoiceStateChangeEvent(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		event  string
+	}{
+		{"join", "", "10", EVENT_VOICE_JOIN},
+		{"leave", "10", "", EVENT_VOICE_LEAVE},
+		{"move", "10", "11", EVENT_VOICE_MOVE},
+		{"mute in the same channel", "10", "10", ""},
+		{"not in a channel", "", "", ""},
+	}
+	for _, test := range tests {
+		change := VoiceStateChange{Before: VoiceState{ChannelID: test.before}, After: VoiceState{ChannelID: test.after}}
+		if event := change.event(); event != test.event {
+			t.Errorf("%s: got event %q, want %q", test.name, event, test.event)
+		}
+	}
+}
+
+func TestUpdateVoiceState(t *testing.T) {
+	d := NewDiscordBot()
+	d.setGuild(Guild{ID: "1", VoiceStates: []VoiceState{{UserID: "2", ChannelID: "10"}}})
+
+	tests := []struct {
+		name   string
+		state  VoiceState
+		before string
+		cached int
+	}{
+		{"other user joins", VoiceState{GuildID: "1", UserID: "3", ChannelID: "10"}, "", 2},
+		{"user mutes", VoiceState{GuildID: "1", UserID: "2", ChannelID: "10", SelfMute: true}, "10", 2},
+		{"user moves", VoiceState{GuildID: "1", UserID: "2", ChannelID: "11"}, "10", 2},
+		{"user leaves", VoiceState{GuildID: "1", UserID: "2"}, "11", 1},
+		{"uncached guild", VoiceState{GuildID: "9", UserID: "2", ChannelID: "10"}, "", 1},
+	}
+	for _, test := range tests {
+		change := d.updateVoiceState(test.state)
+		if change.Before.ChannelID != test.before || change.After != test.state {
+			t.Errorf("%s: got change %+v", test.name, change)
+		}
+		guild, _ := d.GetGuildById("1")
+		if len(guild.VoiceStates) != test.cached {
+			t.Errorf("%s: %d voice states are cached, want %d", test.name, len(guild.VoiceStates), test.cached)
+		}
+	}
+	if state, exists := d.GetVoiceStateByUserId("1", "3"); !exists || state.ChannelID != "10" {
+		t.Errorf("voice state of user 3 is %+v", state)
+	}
+	if states := d.GetVoiceStatesByChannelId("11"); len(states) != 0 {
+		t.Errorf("channel 11 still has voice states %+v", states)
+	}
+}